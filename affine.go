@@ -0,0 +1,213 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// AffineMatrix is a 2x3 affine transformation matrix {a, b, c, d, e, f}
+// that maps the point (x, y) to the point (a*x + b*y + c, d*x + e*y + f).
+// The coordinates are in the image coordinate system: the y axis points down
+// and the pixel (x, y) covers the square from (x, y) to (x+1, y+1).
+type AffineMatrix [6]float32
+
+// IdentityMatrix returns the affine matrix that doesn't change the points.
+func IdentityMatrix() AffineMatrix {
+	return AffineMatrix{1, 0, 0, 0, 1, 0}
+}
+
+// TranslationMatrix returns the affine matrix that moves the points by (tx, ty).
+func TranslationMatrix(tx, ty float32) AffineMatrix {
+	return AffineMatrix{1, 0, tx, 0, 1, ty}
+}
+
+// ScalingMatrix returns the affine matrix that scales the points relative to the origin.
+func ScalingMatrix(sx, sy float32) AffineMatrix {
+	return AffineMatrix{sx, 0, 0, 0, sy, 0}
+}
+
+// RotationMatrix returns the affine matrix that rotates the points around the origin
+// by the given angle in degrees counter-clockwise.
+func RotationMatrix(angle float32) AffineMatrix {
+	asin, acos := sincosf32(angle)
+	return AffineMatrix{acos, asin, 0, -asin, acos, 0}
+}
+
+// ShearMatrix returns the affine matrix that shears the points: x is shifted by shx*y and y is shifted by shy*x.
+func ShearMatrix(shx, shy float32) AffineMatrix {
+	return AffineMatrix{1, shx, 0, shy, 1, 0}
+}
+
+// Multiply returns the matrix of the transformation that applies n first and then m.
+func (m AffineMatrix) Multiply(n AffineMatrix) AffineMatrix {
+	return AffineMatrix{
+		m[0]*n[0] + m[1]*n[3],
+		m[0]*n[1] + m[1]*n[4],
+		m[0]*n[2] + m[1]*n[5] + m[2],
+		m[3]*n[0] + m[4]*n[3],
+		m[3]*n[1] + m[4]*n[4],
+		m[3]*n[2] + m[4]*n[5] + m[5],
+	}
+}
+
+// Translate returns the matrix of the transformation m followed by a translation.
+func (m AffineMatrix) Translate(tx, ty float32) AffineMatrix {
+	return TranslationMatrix(tx, ty).Multiply(m)
+}
+
+// Scale returns the matrix of the transformation m followed by a scaling relative to the origin.
+func (m AffineMatrix) Scale(sx, sy float32) AffineMatrix {
+	return ScalingMatrix(sx, sy).Multiply(m)
+}
+
+// Rotate returns the matrix of the transformation m followed by a counter-clockwise rotation around the origin.
+func (m AffineMatrix) Rotate(angle float32) AffineMatrix {
+	return RotationMatrix(angle).Multiply(m)
+}
+
+// RotateAround returns the matrix of the transformation m followed by a counter-clockwise rotation around the point (cx, cy).
+func (m AffineMatrix) RotateAround(angle, cx, cy float32) AffineMatrix {
+	return m.Translate(-cx, -cy).Rotate(angle).Translate(cx, cy)
+}
+
+// Shear returns the matrix of the transformation m followed by a shear.
+func (m AffineMatrix) Shear(shx, shy float32) AffineMatrix {
+	return ShearMatrix(shx, shy).Multiply(m)
+}
+
+// Invert returns the matrix of the inverse transformation.
+// If the matrix is not invertible, the second return value is false.
+func (m AffineMatrix) Invert() (AffineMatrix, bool) {
+	det := m[0]*m[4] - m[1]*m[3]
+	if absf32(det) < 1e-12 {
+		return AffineMatrix{}, false
+	}
+	q := 1 / det
+	return AffineMatrix{
+		m[4] * q,
+		-m[1] * q,
+		(m[1]*m[5] - m[2]*m[4]) * q,
+		-m[3] * q,
+		m[0] * q,
+		(m[2]*m[3] - m[0]*m[5]) * q,
+	}, true
+}
+
+// Transform returns the transformed point.
+func (m AffineMatrix) Transform(x, y float32) (float32, float32) {
+	return m[0]*x + m[1]*y + m[2], m[3]*x + m[4]*y + m[5]
+}
+
+// BoundsMode defines the bounds of the image produced by a geometric transformation.
+type BoundsMode int
+
+// Bounds modes.
+const (
+	// KeepBoundsMode keeps the size and the position of the original image.
+	// The parts of the transformed image outside of the original bounds are cut off.
+	KeepBoundsMode BoundsMode = iota
+	// FitBoundsMode expands or shrinks the bounds to fit the whole transformed image.
+	FitBoundsMode
+)
+
+// transformedBounds returns the bounding box of the rectangle transformed by the given function.
+func transformedBounds(r image.Rectangle, fn func(x, y float32) (float32, float32)) (minx, miny, maxx, maxy float32) {
+	x0, y0 := float32(r.Min.X), float32(r.Min.Y)
+	x1, y1 := float32(r.Max.X), float32(r.Max.Y)
+	for i, pt := range [4][2]float32{{x0, y0}, {x1, y0}, {x1, y1}, {x0, y1}} {
+		x, y := fn(pt[0], pt[1])
+		if i == 0 {
+			minx, miny, maxx, maxy = x, y, x, y
+			continue
+		}
+		minx, maxx = minf32(minx, x), maxf32(maxx, x)
+		miny, maxy = minf32(miny, y), maxf32(maxy, y)
+	}
+	return
+}
+
+// warpGeometry calculates the size of the transformed image and the position of its top-left corner
+// in the coordinate system of the transformed src image.
+func warpGeometry(srcb image.Rectangle, mode BoundsMode, fn func(x, y float32) (float32, float32)) (w, h int, ox, oy float32) {
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 {
+		return 0, 0, 0, 0
+	}
+	if mode != FitBoundsMode {
+		return srcb.Dx(), srcb.Dy(), float32(srcb.Min.X), float32(srcb.Min.Y)
+	}
+	minx, miny, maxx, maxy := transformedBounds(srcb, fn)
+	fw, fh := maxx-minx, maxy-miny
+	// Ignore small errors to avoid extra rows and columns of background.
+	w = int(floorf32(fw + 0.99))
+	h = int(floorf32(fh + 0.99))
+	if w <= 0 || h <= 0 {
+		return 0, 0, 0, 0
+	}
+	ox = (minx+maxx)/2 - float32(w)/2
+	oy = (miny+maxy)/2 - float32(h)/2
+	return w, h, ox, oy
+}
+
+type affineFilter struct {
+	matrix        AffineMatrix
+	mode          BoundsMode
+	bgcolor       color.Color
+	interpolation Interpolation
+}
+
+func (p *affineFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h, _, _ := warpGeometry(srcBounds.Sub(srcBounds.Min), p.mode, p.matrix.Transform)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *affineFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	// The matrix is applied to the coordinates relative to the top-left corner of the src image.
+	srcb := src.Bounds()
+	w, h, ox, oy := warpGeometry(srcb.Sub(srcb.Min), p.mode, p.matrix.Transform)
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	inv, ok := p.matrix.Invert()
+	minx, miny := float32(srcb.Min.X), float32(srcb.Min.Y)
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		if !ok {
+			return 0, 0, false
+		}
+		xf, yf := inv.Transform(ox+x+0.5, oy+y+0.5)
+		return minx + xf - 0.5, miny + yf - 0.5, true
+	}, interpolator{interpolation: p.interpolation}, pixelFromColor(p.bgcolor), options)
+}
+
+// Affine creates a filter that applies an arbitrary affine transformation to an image.
+// The matrix parameter maps the points of the src image to the points of the transformed image,
+// the coordinates are relative to the top-left corner of the src image bounds.
+// Use the matrix constructors and methods (e.g. RotationMatrix, AffineMatrix.Translate) to build it.
+// The mode parameter specifies whether the original bounds are kept or the bounds are fitted to the transformed image.
+// The backgroundColor parameter specifies the color of the areas not covered by the transformed image.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	// Rotate the src image by 30 degrees around the point (100, 50) keeping its bounds.
+//	m := gift.IdentityMatrix().RotateAround(30, 100, 50)
+//	g := gift.New(
+//		gift.Affine(m, gift.KeepBoundsMode, color.Transparent, gift.LinearInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Affine(matrix AffineMatrix, mode BoundsMode, backgroundColor color.Color, interpolation Interpolation) Filter {
+	return &affineFilter{
+		matrix:        matrix,
+		mode:          mode,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
@@ -0,0 +1,230 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestAffineMatrix(t *testing.T) {
+	near := func(x0, y0, x1, y1 float32) bool {
+		return absf32(x0-x1) < 1e-4 && absf32(y0-y1) < 1e-4
+	}
+
+	testData := []struct {
+		desc   string
+		m      AffineMatrix
+		x, y   float32
+		tx, ty float32
+	}{
+		{"identity", IdentityMatrix(), 3, 4, 3, 4},
+		{"translation", TranslationMatrix(1, -2), 3, 4, 4, 2},
+		{"scaling", ScalingMatrix(2, 0.5), 3, 4, 6, 2},
+		{"rotation", RotationMatrix(90), 1, 0, 0, -1},
+		{"rotation 180", RotationMatrix(180), 1, 2, -1, -2},
+		{"shear", ShearMatrix(1, 0), 3, 4, 7, 4},
+		{"translate then scale", IdentityMatrix().Translate(1, 1).Scale(2, 3), 1, 1, 4, 6},
+		{"scale then translate", IdentityMatrix().Scale(2, 3).Translate(1, 1), 1, 1, 3, 4},
+		{"rotate around", IdentityMatrix().RotateAround(90, 1, 1), 2, 1, 1, 0},
+		{"shear method", IdentityMatrix().Shear(0, 2), 1, 1, 1, 3},
+		{"rotate method", IdentityMatrix().Rotate(-90), 1, 0, 0, 1},
+		{"multiply", TranslationMatrix(1, 0).Multiply(ScalingMatrix(2, 2)), 1, 1, 3, 2},
+	}
+
+	for _, d := range testData {
+		x, y := d.m.Transform(d.x, d.y)
+		if !near(x, y, d.tx, d.ty) {
+			t.Errorf("test [%s]: expected (%v, %v) got (%v, %v)", d.desc, d.tx, d.ty, x, y)
+		}
+		inv, ok := d.m.Invert()
+		if !ok {
+			t.Errorf("test [%s]: expected invertible matrix", d.desc)
+			continue
+		}
+		x, y = inv.Transform(d.tx, d.ty)
+		if !near(x, y, d.x, d.y) {
+			t.Errorf("test [%s]: inverse: expected (%v, %v) got (%v, %v)", d.desc, d.x, d.y, x, y)
+		}
+	}
+
+	if _, ok := ScalingMatrix(0, 1).Invert(); ok {
+		t.Errorf("expected singular matrix")
+	}
+}
+
+func TestAffine(t *testing.T) {
+	testData := []struct {
+		desc           string
+		m              AffineMatrix
+		mode           BoundsMode
+		interp         Interpolation
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"affine identity keep",
+			IdentityMatrix(), KeepBoundsMode, CubicInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+		},
+		{
+			"affine translation keep",
+			TranslationMatrix(1, 0), KeepBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0xff, 0x01, 0x02,
+				0xff, 0x04, 0x05,
+			},
+		},
+		{
+			"affine translation fit",
+			TranslationMatrix(10, 5), FitBoundsMode, LinearInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+		},
+		{
+			"affine scaling fit",
+			ScalingMatrix(2, 1), FitBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 6, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x01, 0x02, 0x02, 0x03, 0x03,
+				0x04, 0x04, 0x05, 0x05, 0x06, 0x06,
+			},
+		},
+		{
+			"affine rotation fit",
+			RotationMatrix(90), FitBoundsMode, LinearInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 2, 3),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x03, 0x06,
+				0x02, 0x05,
+				0x01, 0x04,
+			},
+		},
+		{
+			"affine shear fit",
+			ShearMatrix(1, 0), FitBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 5, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03, 0xff, 0xff,
+				0xff, 0x04, 0x05, 0x06, 0xff,
+			},
+		},
+		{
+			"affine downscaling area",
+			ScalingMatrix(0.5, 0.5), FitBoundsMode, AreaInterpolation,
+			image.Rect(-1, -1, 3, 1),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x10, 0x20, 0x80, 0x80,
+				0x30, 0x40, 0x00, 0x00,
+			},
+			[]uint8{
+				0x28, 0x40,
+			},
+		},
+		{
+			"affine singular keep",
+			ScalingMatrix(0, 0), KeepBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"affine singular fit",
+			ScalingMatrix(0, 0), FitBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{},
+		},
+		{
+			"affine 0x0",
+			IdentityMatrix(), FitBoundsMode, NearestNeighborInterpolation,
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		f := Affine(d.m, d.mode, color.White, d.interp)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestAffineSubImage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 6, 4))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(i + 1)
+	}
+	src := img.SubImage(image.Rect(2, 1, 5, 3))
+
+	// The matrix is applied relative to the top-left corner of the src image.
+	f := Affine(ScalingMatrix(2, 2), KeepBoundsMode, color.White, NearestNeighborInterpolation)
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+
+	want := []uint8{
+		0x09, 0x09, 0x0a,
+		0x09, 0x09, 0x0a,
+	}
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 3, 2), dst.Pix, want) {
+		t.Errorf("affine subimage failed: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+}
@@ -0,0 +1,113 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+)
+
+// BorderMode defines how the neighborhood filters and the interpolators extend an image beyond its bounds.
+type BorderMode int
+
+// Border modes.
+const (
+	// ClampBorderMode repeats the edge pixels: aaa|abcd|ddd.
+	ClampBorderMode BorderMode = iota
+	// ReflectBorderMode mirrors the image including the edge pixels: cba|abcd|dcb.
+	ReflectBorderMode
+	// Reflect101BorderMode mirrors the image around the edge pixels: dcb|abcd|cba.
+	Reflect101BorderMode
+	// WrapBorderMode tiles the image: bcd|abcd|abc.
+	WrapBorderMode
+	// ConstantBorderMode fills the area beyond the bounds with a constant color: xxx|abcd|xxx.
+	ConstantBorderMode
+)
+
+// borderIndex maps the index i to the range [0, n) according to the border mode.
+// It returns false if the mode is ConstantBorderMode and i is out of the range.
+func borderIndex(i, n int, mode BorderMode) (int, bool) {
+	if i >= 0 && i < n {
+		return i, true
+	}
+	switch mode {
+	case ReflectBorderMode:
+		i %= 2 * n
+		if i < 0 {
+			i += 2 * n
+		}
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+	case Reflect101BorderMode:
+		if n == 1 {
+			return 0, true
+		}
+		i %= 2*n - 2
+		if i < 0 {
+			i += 2*n - 2
+		}
+		if i >= n {
+			i = 2*n - 2 - i
+		}
+	case WrapBorderMode:
+		i %= n
+		if i < 0 {
+			i += n
+		}
+	case ConstantBorderMode:
+		return 0, false
+	default:
+		if i < 0 {
+			i = 0
+		} else {
+			i = n - 1
+		}
+	}
+	return i, true
+}
+
+// borderPixel returns the color of the pixels beyond the image bounds in ConstantBorderMode.
+// If the color is nil, the pixels are transparent.
+func borderPixel(c color.Color) pixel {
+	if c == nil {
+		return pixel{}
+	}
+	return pixelFromColor(c)
+}
+
+// borderPixelGetter gets the pixels of an image extended beyond its bounds according to the border mode.
+type borderPixelGetter struct {
+	pixGetter *pixelGetter
+	bounds    image.Rectangle
+	mode      BorderMode
+	px        pixel
+}
+
+func newBorderPixelGetter(img image.Image, mode BorderMode, px pixel) *borderPixelGetter {
+	return &borderPixelGetter{
+		pixGetter: newPixelGetter(img),
+		bounds:    img.Bounds(),
+		mode:      mode,
+		px:        px,
+	}
+}
+
+// covers reports whether the point (x, y) is close enough to the image to be interpolated from its pixels:
+// it is within the bounds extended by one pixel to the left and top or the image is extended infinitely.
+func (p *borderPixelGetter) covers(x, y int) bool {
+	if p.mode != ConstantBorderMode {
+		return true
+	}
+	return image.Pt(x, y).In(image.Rect(p.bounds.Min.X-1, p.bounds.Min.Y-1, p.bounds.Max.X, p.bounds.Max.Y))
+}
+
+func (p *borderPixelGetter) getPixel(x, y int) pixel {
+	if image.Pt(x, y).In(p.bounds) {
+		return p.pixGetter.getPixel(x, y)
+	}
+	ix, okx := borderIndex(x-p.bounds.Min.X, p.bounds.Dx(), p.mode)
+	iy, oky := borderIndex(y-p.bounds.Min.Y, p.bounds.Dy(), p.mode)
+	if !okx || !oky {
+		return p.px
+	}
+	return p.pixGetter.getPixel(p.bounds.Min.X+ix, p.bounds.Min.Y+iy)
+}
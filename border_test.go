@@ -0,0 +1,121 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestBorderIndex(t *testing.T) {
+	testData := []struct {
+		mode BorderMode
+		n    int
+		want []int // The indices from -3 to n+2, -1 means out of range.
+	}{
+		{ClampBorderMode, 4, []int{0, 0, 0, 0, 1, 2, 3, 3, 3, 3}},
+		{ReflectBorderMode, 4, []int{2, 1, 0, 0, 1, 2, 3, 3, 2, 1}},
+		{Reflect101BorderMode, 4, []int{3, 2, 1, 0, 1, 2, 3, 2, 1, 0}},
+		{WrapBorderMode, 4, []int{1, 2, 3, 0, 1, 2, 3, 0, 1, 2}},
+		{ConstantBorderMode, 4, []int{-1, -1, -1, 0, 1, 2, 3, -1, -1, -1}},
+		{ReflectBorderMode, 2, []int{1, 1, 0, 0, 1, 1, 0, 0}},
+		{Reflect101BorderMode, 1, []int{0, 0, 0, 0, 0, 0, 0}},
+		{WrapBorderMode, 1, []int{0, 0, 0, 0, 0, 0, 0}},
+	}
+
+	for _, d := range testData {
+		for i := -3; i < d.n+3; i++ {
+			want := d.want[i+3]
+			got, ok := borderIndex(i, d.n, d.mode)
+			if !ok {
+				got = -1
+			}
+			if got != want {
+				t.Errorf("mode %d, n %d, index %d: expected %d got %d", d.mode, d.n, i, want, got)
+			}
+		}
+	}
+
+	// Indices far beyond the bounds.
+	if i, _ := borderIndex(-9, 4, ReflectBorderMode); i != 0 {
+		t.Errorf("reflect -9: expected 0 got %d", i)
+	}
+	if i, _ := borderIndex(21, 4, Reflect101BorderMode); i != 3 {
+		t.Errorf("reflect101 21: expected 3 got %d", i)
+	}
+	if i, _ := borderIndex(-13, 4, WrapBorderMode); i != 3 {
+		t.Errorf("wrap -13: expected 3 got %d", i)
+	}
+}
+
+func TestBorderModes(t *testing.T) {
+	// The convolution kernel that moves the image 1 pixel to the right.
+	shift := []float32{
+		0, 0, 0,
+		1, 0, 0,
+		0, 0, 0,
+	}
+
+	testData := []struct {
+		desc           string
+		f              Filter
+		mode           BorderMode
+		color          color.Color
+		srcPix, dstPix []uint8
+	}{
+		{"convolution clamp", Convolution(shift, false, false, false, 0), ClampBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x10, 0x10, 0x20, 0x30}},
+		{"convolution reflect", Convolution(shift, false, false, false, 0), ReflectBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x10, 0x10, 0x20, 0x30}},
+		{"convolution reflect101", Convolution(shift, false, false, false, 0), Reflect101BorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x20, 0x10, 0x20, 0x30}},
+		{"convolution wrap", Convolution(shift, false, false, false, 0), WrapBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x40, 0x10, 0x20, 0x30}},
+		{"convolution constant", Convolution(shift, false, false, false, 0), ConstantBorderMode, color.Gray{0x80}, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x80, 0x10, 0x20, 0x30}},
+		{"mean clamp", Mean(3, false), ClampBorderMode, nil, []uint8{0x00, 0x30, 0x30, 0x60}, []uint8{0x10, 0x20, 0x40, 0x50}},
+		{"mean wrap", Mean(3, false), WrapBorderMode, nil, []uint8{0x00, 0x30, 0x30, 0x60}, []uint8{0x30, 0x20, 0x40, 0x30}},
+		{"mean constant", Mean(3, false), ConstantBorderMode, color.Black, []uint8{0x00, 0x30, 0x30, 0x60}, []uint8{0x05, 0x0b, 0x15, 0x10}},
+		{"minimum clamp", Minimum(3, false), ClampBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x10, 0x10, 0x20, 0x30}},
+		{"minimum wrap", Minimum(3, false), WrapBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x10, 0x10, 0x20, 0x10}},
+		{"minimum reflect101", Minimum(3, false), Reflect101BorderMode, nil, []uint8{0x40, 0x30, 0x20, 0x10}, []uint8{0x30, 0x20, 0x10, 0x10}},
+		{"maximum constant", Maximum(3, false), ConstantBorderMode, color.White, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0xff, 0xff, 0xff, 0xff}},
+		{"median reflect", Median(3, false), ReflectBorderMode, nil, []uint8{0x10, 0x80, 0x20, 0x30}, []uint8{0x10, 0x20, 0x30, 0x30}},
+		{"affine clamp", Affine(TranslationMatrix(1, 0), KeepBoundsMode, color.White, NearestNeighborInterpolation), ClampBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0xff, 0x10, 0x20, 0x30}},
+		{"affine constant", Affine(TranslationMatrix(1, 0), KeepBoundsMode, color.White, LinearInterpolation), ConstantBorderMode, color.Black, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0xff, 0x10, 0x20, 0x30}},
+		{"affine wrap", Affine(TranslationMatrix(1, 0), KeepBoundsMode, color.White, CubicInterpolation), WrapBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x40, 0x10, 0x20, 0x30}},
+		{"affine reflect", Affine(TranslationMatrix(-2, 0), KeepBoundsMode, color.White, LanczosInterpolation), ReflectBorderMode, nil, []uint8{0x10, 0x20, 0x30, 0x40}, []uint8{0x30, 0x40, 0x40, 0x30}},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(image.Rect(0, 0, 4, 1))
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, &Options{BorderMode: d.mode, BorderColor: d.color})
+
+		if !checkBoundsAndPix(dst.Bounds(), src.Bounds(), dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestBorderModeTileable(t *testing.T) {
+	// A tileable texture stays tileable after a blur in the wrap mode.
+	src := image.NewGray(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if x%2 == 0 && y%4 == 0 {
+				src.Pix[y*src.Stride+x] = 0xff
+			}
+		}
+	}
+
+	g := New(GaussianBlur(1.5))
+	g.SetBorderMode(WrapBorderMode)
+	dst := image.NewGray(g.Bounds(src.Bounds()))
+	g.Draw(dst, src)
+
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			want := dst.Pix[(y%4)*dst.Stride+x%2]
+			if got := dst.Pix[y*dst.Stride+x]; absf32(float32(got)-float32(want)) > 1 {
+				t.Fatalf("pixel (%d, %d): expected %#x got %#x", x, y, want, got)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package gift
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 	"math"
 )
@@ -91,15 +92,42 @@ func Invert() Filter {
 	}
 }
 
+func srgbToLinear(x float32) float32 {
+	if x <= 0.04045 {
+		return x / 12.92
+	}
+	return float32(math.Pow(float64((x+0.055)/1.055), 2.4))
+}
+
+func linearToSRGB(x float32) float32 {
+	if x <= 0.0031308 {
+		return x * 12.92
+	}
+	return float32(1.055*math.Pow(float64(x), 1/2.4) - 0.055)
+}
+
+// pixelsToLinear converts the colors of the pixels from sRGB to linear RGB in place.
+func pixelsToLinear(buf []pixel) {
+	for i := range buf {
+		buf[i].r = srgbToLinear(buf[i].r)
+		buf[i].g = srgbToLinear(buf[i].g)
+		buf[i].b = srgbToLinear(buf[i].b)
+	}
+}
+
+// pixelsToSRGB converts the colors of the pixels from linear RGB to sRGB in place.
+func pixelsToSRGB(buf []pixel) {
+	for i := range buf {
+		buf[i].r = linearToSRGB(buf[i].r)
+		buf[i].g = linearToSRGB(buf[i].g)
+		buf[i].b = linearToSRGB(buf[i].b)
+	}
+}
+
 // ColorspaceSRGBToLinear creates a filter that converts the colors of an image from sRGB to linear RGB.
 func ColorspaceSRGBToLinear() Filter {
 	return &colorchanFilter{
-		fn: func(x float32) float32 {
-			if x <= 0.04045 {
-				return x / 12.92
-			}
-			return float32(math.Pow(float64((x+0.055)/1.055), 2.4))
-		},
+		fn:  srgbToLinear,
 		lut: true,
 	}
 }
@@ -107,12 +135,7 @@ func ColorspaceSRGBToLinear() Filter {
 // ColorspaceLinearToSRGB creates a filter that converts the colors of an image from linear RGB to sRGB.
 func ColorspaceLinearToSRGB() Filter {
 	return &colorchanFilter{
-		fn: func(x float32) float32 {
-			if x <= 0.0031308 {
-				return x * 12.92
-			}
-			return float32(1.055*math.Pow(float64(x), 1/2.4) - 0.055)
-		},
+		fn:  linearToSRGB,
 		lut: true,
 	}
 }
@@ -367,6 +390,64 @@ func normalizeHue(hue float32) float32 {
 	return hue
 }
 
+// D65 reference white.
+const (
+	whiteX = 0.95047
+	whiteY = 1.00000
+	whiteZ = 1.08883
+)
+
+func labF(t float32) float32 {
+	if t > 216.0/24389.0 {
+		return float32(math.Cbrt(float64(t)))
+	}
+	return (24389.0/27.0*t + 16) / 116
+}
+
+func labFInv(t float32) float32 {
+	if t > 6.0/29.0 {
+		return t * t * t
+	}
+	return (116*t - 16) * 27.0 / 24389.0
+}
+
+// convertRGBToLab converts sRGB color components to CIE L*a*b* (D65).
+// L is in range (0, 100), a and b are typically in range (-128, 128).
+func convertRGBToLab(r, g, b float32) (float32, float32, float32) {
+	r, g, b = srgbToLinear(r), srgbToLinear(g), srgbToLinear(b)
+
+	x := 0.4124564*r + 0.3575761*g + 0.1804375*b
+	y := 0.2126729*r + 0.7151522*g + 0.0721750*b
+	z := 0.0193339*r + 0.1191920*g + 0.9503041*b
+
+	fx := labF(x / whiteX)
+	fy := labF(y / whiteY)
+	fz := labF(z / whiteZ)
+
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+// convertLabToRGB converts CIE L*a*b* (D65) color components to sRGB.
+func convertLabToRGB(l, a, b float32) (float32, float32, float32) {
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+
+	x := labFInv(fx) * whiteX
+	y := labFInv(fy) * whiteY
+	z := labFInv(fz) * whiteZ
+
+	r := 3.2404542*x - 1.5371385*y - 0.4985314*z
+	g := -0.9692660*x + 1.8760108*y + 0.0415560*z
+	bl := 0.0556434*x - 0.2040259*y + 1.0572252*z
+
+	r = linearToSRGB(minf32(maxf32(r, 0), 1))
+	g = linearToSRGB(minf32(maxf32(g, 0), 1))
+	bl = linearToSRGB(minf32(maxf32(bl, 0), 1))
+
+	return r, g, bl
+}
+
 // Hue creates a filter that rotates the hue of an image.
 // The shift parameter is the hue angle shift, typically in range (-180, 180).
 // The shift = 0 gives the original image.
@@ -440,6 +521,564 @@ func Colorize(hue, saturation, percentage float32) Filter {
 	}
 }
 
+// GradientStop is a color stop of a gradient.
+// The Position field specifies the position of the stop in range (0, 1).
+type GradientStop struct {
+	Position float32
+	Color    color.Color
+}
+
+// GradientSpace is the color space used to interpolate colors between gradient stops.
+type GradientSpace int
+
+// Gradient interpolation color spaces.
+const (
+	// GradientSRGB interpolates the sRGB color components directly.
+	GradientSRGB GradientSpace = iota
+	// GradientLinear interpolates the color components in linear RGB.
+	GradientLinear
+	// GradientLab interpolates the colors in CIE L*a*b* space.
+	GradientLab
+)
+
+const gradientLutSize = 1024
+
+// prepareGradientLut samples the gradient into a lookup table of the given size.
+func prepareGradientLut(stops []GradientStop, space GradientSpace, lutSize int) []pixel {
+	type stop struct {
+		pos           float32
+		c0, c1, c2, a float32
+	}
+
+	sorted := make([]stop, 0, len(stops))
+	for _, st := range stops {
+		px := pixelFromColor(st.Color)
+		var c0, c1, c2 float32
+		switch space {
+		case GradientLinear:
+			c0, c1, c2 = srgbToLinear(px.r), srgbToLinear(px.g), srgbToLinear(px.b)
+		case GradientLab:
+			c0, c1, c2 = convertRGBToLab(px.r, px.g, px.b)
+		default:
+			c0, c1, c2 = px.r, px.g, px.b
+		}
+		s := stop{pos: minf32(maxf32(st.Position, 0), 1), c0: c0, c1: c1, c2: c2, a: px.a}
+		i := len(sorted)
+		sorted = append(sorted, s)
+		for ; i > 0 && sorted[i-1].pos > s.pos; i-- {
+			sorted[i] = sorted[i-1]
+		}
+		sorted[i] = s
+	}
+
+	lut := make([]pixel, lutSize)
+	q := 1 / float32(lutSize-1)
+	k := 0
+	for v := 0; v < lutSize; v++ {
+		u := float32(v) * q
+		for k < len(sorted)-1 && sorted[k+1].pos < u {
+			k++
+		}
+
+		var c0, c1, c2, a float32
+		s0 := sorted[k]
+		if u <= s0.pos || k == len(sorted)-1 {
+			c0, c1, c2, a = s0.c0, s0.c1, s0.c2, s0.a
+		} else {
+			s1 := sorted[k+1]
+			t := (u - s0.pos) / (s1.pos - s0.pos)
+			c0 = s0.c0 + (s1.c0-s0.c0)*t
+			c1 = s0.c1 + (s1.c1-s0.c1)*t
+			c2 = s0.c2 + (s1.c2-s0.c2)*t
+			a = s0.a + (s1.a-s0.a)*t
+		}
+
+		switch space {
+		case GradientLinear:
+			c0, c1, c2 = linearToSRGB(c0), linearToSRGB(c1), linearToSRGB(c2)
+		case GradientLab:
+			c0, c1, c2 = convertLabToRGB(c0, c1, c2)
+		}
+		lut[v] = pixel{c0, c1, c2, a}
+	}
+	return lut
+}
+
+// GradientMap creates a filter that maps the luminance of each pixel to a color of a multi-stop gradient.
+// Black pixels are mapped to the color at position 0 and white pixels to the color at position 1.
+// The stops parameter specifies the gradient color stops, they are sorted by position.
+// A stop with a nil color is transparent. If there are no stops, the image is left unchanged.
+// The space parameter specifies the color space used to interpolate colors between stops.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.GradientMap(
+//			[]gift.GradientStop{
+//				{Position: 0, Color: color.NRGBA{0x20, 0x00, 0x40, 0xff}},
+//				{Position: 0.5, Color: color.NRGBA{0xe0, 0x40, 0x40, 0xff}},
+//				{Position: 1, Color: color.NRGBA{0xff, 0xf0, 0xa0, 0xff}},
+//			},
+//			gift.GradientLab,
+//		),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func GradientMap(stops []GradientStop, space GradientSpace) Filter {
+	if len(stops) == 0 {
+		return &copyimageFilter{}
+	}
+
+	// Copy the stops so the caller's slice isn't modified.
+	validStops := make([]GradientStop, len(stops))
+	for i, st := range stops {
+		if st.Color == nil {
+			st.Color = color.Transparent
+		}
+		validStops[i] = st
+	}
+
+	lut := prepareGradientLut(validStops, space, gradientLutSize)
+	last := float32(len(lut) - 1)
+
+	return &colorFilter{
+		fn: func(px pixel) pixel {
+			y := minf32(maxf32(0.299*px.r+0.587*px.g+0.114*px.b, 0), 1)
+			c := lut[int(y*last+0.5)]
+			c.a *= px.a
+			return c
+		},
+	}
+}
+
+// Duotone creates a filter that produces a two-color version of an image.
+// Dark pixels are mapped to the shadows color and light pixels to the highlights color.
+// It is a shortcut for a two-stop GradientMap interpolated in sRGB.
+func Duotone(shadows, highlights color.Color) Filter {
+	return GradientMap(
+		[]GradientStop{
+			{Position: 0, Color: shadows},
+			{Position: 1, Color: highlights},
+		},
+		GradientSRGB,
+	)
+}
+
+// bayer4 is a 4x4 ordered dithering matrix.
+var bayer4 = [16]float32{
+	0, 8, 2, 10,
+	12, 4, 14, 6,
+	3, 11, 1, 9,
+	15, 7, 13, 5,
+}
+
+type posterizeFilter struct {
+	levels [3]int
+	dither bool
+}
+
+func (p *posterizeFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+	return
+}
+
+func posterize(v float32, n, threshold float32) float32 {
+	v = minf32(maxf32(v, 0), 1)
+	return minf32(floorf32(v*n+threshold), n) / n
+}
+
+func (p *posterizeFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+	pixGetter := newPixelGetter(src)
+	pixSetter := newPixelSetter(dst)
+
+	nr := float32(p.levels[0] - 1)
+	ng := float32(p.levels[1] - 1)
+	nb := float32(p.levels[2] - 1)
+
+	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := srcb.Min.X; x < srcb.Max.X; x++ {
+				px := pixGetter.getPixel(x, y)
+				threshold := float32(0.5)
+				if p.dither {
+					i := ((y-srcb.Min.Y)&3)*4 + (x-srcb.Min.X)&3
+					threshold = (bayer4[i] + 0.5) / 16
+				}
+				px.r = posterize(px.r, nr, threshold)
+				px.g = posterize(px.g, ng, threshold)
+				px.b = posterize(px.b, nb, threshold)
+				pixSetter.setPixel(dstb.Min.X+x-srcb.Min.X, dstb.Min.Y+y-srcb.Min.Y, px)
+			}
+		}
+	})
+}
+
+// Posterize creates a filter that reduces the number of color levels in each channel of an image.
+// The levels parameter is the number of levels per channel, it must be in range (2, 256).
+// If the dither parameter is true, ordered dithering is used to reduce color banding.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.Posterize(4, false),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Posterize(levels int, dither bool) Filter {
+	return PosterizeChannels(levels, levels, levels, dither)
+}
+
+// PosterizeChannels creates a filter that reduces the number of color levels in each channel of an image
+// using a separate number of levels for the red, green and blue channels.
+// The levels parameters must be in range (2, 256).
+// If the dither parameter is true, ordered dithering is used to reduce color banding.
+func PosterizeChannels(levelsRed, levelsGreen, levelsBlue int, dither bool) Filter {
+	clamp := func(n int) int {
+		return minint(maxint(n, 2), 256)
+	}
+	return &posterizeFilter{
+		levels: [3]int{clamp(levelsRed), clamp(levelsGreen), clamp(levelsBlue)},
+		dither: dither,
+	}
+}
+
+// ColorRange is a range of colors adjusted by the SelectiveColor filter.
+type ColorRange int
+
+// Color ranges.
+const (
+	RedsColorRange ColorRange = iota
+	YellowsColorRange
+	GreensColorRange
+	CyansColorRange
+	BluesColorRange
+	MagentasColorRange
+	WhitesColorRange
+	NeutralsColorRange
+	BlacksColorRange
+)
+
+// SelectiveColorAdjustment is an adjustment of a color range used by the SelectiveColor filter.
+// The Cyan, Magenta, Yellow and Black fields are the amounts of the corresponding process colors
+// to add to (positive) or remove from (negative) the colors within the range.
+// They must be in range (-100, 100).
+type SelectiveColorAdjustment struct {
+	Range   ColorRange
+	Cyan    float32
+	Magenta float32
+	Yellow  float32
+	Black   float32
+}
+
+// colorRangeWeights calculates how much the given color belongs to each of the color ranges.
+func colorRangeWeights(r, g, b float32) (w [9]float32) {
+	max := maxf32(r, maxf32(g, b))
+	min := minf32(r, minf32(g, b))
+	mid := r + g + b - max - min
+
+	// Primary colors are weighted by the distance between the max and the mid channel,
+	// secondary colors by the distance between the mid and the min channel.
+	if max > mid {
+		switch max {
+		case r:
+			w[RedsColorRange] = max - mid
+		case g:
+			w[GreensColorRange] = max - mid
+		default:
+			w[BluesColorRange] = max - mid
+		}
+	}
+	if mid > min {
+		switch min {
+		case b:
+			w[YellowsColorRange] = mid - min
+		case r:
+			w[CyansColorRange] = mid - min
+		default:
+			w[MagentasColorRange] = mid - min
+		}
+	}
+
+	if min > 0.5 {
+		w[WhitesColorRange] = (min - 0.5) * 2
+	}
+	if max < 0.5 {
+		w[BlacksColorRange] = (0.5 - max) * 2
+	}
+	w[NeutralsColorRange] = maxf32(1-absf32(max-0.5)-absf32(min-0.5), 0)
+
+	return w
+}
+
+// selectiveColorDelta calculates the change of a color channel value v
+// given the amount of the complementary process color and black.
+func selectiveColorDelta(v, amount, black float32, relative bool) float32 {
+	d := -amount - (1+amount)*black
+	if relative {
+		d *= 1 - v
+	}
+	return d
+}
+
+// SelectiveColor creates a filter that adjusts the amount of process colors (cyan, magenta, yellow and black)
+// within the specified color ranges without affecting the other colors, similar to the Selective Color
+// adjustment found in image editors.
+// If the relative parameter is true, the amounts are changed by a percentage of their existing values,
+// otherwise the amounts are changed by an absolute value.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.SelectiveColor(
+//			[]gift.SelectiveColorAdjustment{
+//				{Range: gift.RedsColorRange, Cyan: -30, Yellow: 20},
+//				{Range: gift.BlacksColorRange, Black: 10},
+//			},
+//			true,
+//		),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func SelectiveColor(adjustments []SelectiveColorAdjustment, relative bool) Filter {
+	var adj [9][4]float32
+	active := false
+	for _, a := range adjustments {
+		if a.Range < RedsColorRange || a.Range > BlacksColorRange {
+			continue
+		}
+		adj[a.Range][0] = minf32(maxf32(a.Cyan, -100), 100) / 100
+		adj[a.Range][1] = minf32(maxf32(a.Magenta, -100), 100) / 100
+		adj[a.Range][2] = minf32(maxf32(a.Yellow, -100), 100) / 100
+		adj[a.Range][3] = minf32(maxf32(a.Black, -100), 100) / 100
+		active = active || adj[a.Range] != [4]float32{}
+	}
+	if !active {
+		return &copyimageFilter{}
+	}
+
+	return &colorFilter{
+		fn: func(px pixel) pixel {
+			r := minf32(maxf32(px.r, 0), 1)
+			g := minf32(maxf32(px.g, 0), 1)
+			b := minf32(maxf32(px.b, 0), 1)
+			w := colorRangeWeights(r, g, b)
+			var dr, dg, db float32
+			for i := range w {
+				if w[i] == 0 || adj[i] == [4]float32{} {
+					continue
+				}
+				dr += selectiveColorDelta(r, adj[i][0], adj[i][3], relative) * w[i]
+				dg += selectiveColorDelta(g, adj[i][1], adj[i][3], relative) * w[i]
+				db += selectiveColorDelta(b, adj[i][2], adj[i][3], relative) * w[i]
+			}
+			px.r = minf32(maxf32(r+dr, 0), 1)
+			px.g = minf32(maxf32(g+dg, 0), 1)
+			px.b = minf32(maxf32(b+db, 0), 1)
+			return px
+		},
+	}
+}
+
+// ChromaKey creates a filter that makes the pixels close to the key color transparent.
+// The color difference is measured in the a*b* chromaticity plane of the CIE L*a*b* color space,
+// so that uneven lighting of the backdrop doesn't affect the result.
+// The tolerance parameter is the color difference below which pixels become fully transparent, typically 20-40.
+// The softness parameter is the width of the transition zone above the tolerance where pixels
+// become partially transparent, typically 10-30.
+// The spill parameter specifies how much of the key color cast is removed from the remaining pixels,
+// it must be in range (0, 100).
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.ChromaKey(color.NRGBA{0x00, 0xb1, 0x40, 0xff}, 30, 20, 100), // green screen
+//	)
+//	dst := image.NewNRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ChromaKey(key color.Color, tolerance, softness, spill float32) Filter {
+	kpx := pixelFromColor(key)
+	_, ka, kb := convertRGBToLab(kpx.r, kpx.g, kpx.b)
+	tol := maxf32(tolerance, 0)
+	soft := maxf32(softness, 0)
+	sp := minf32(maxf32(spill, 0), 100) / 100
+
+	// Unit vector of the key chroma direction.
+	kc := sqrtf32(ka*ka + kb*kb)
+	var kda, kdb float32
+	if kc > 0 {
+		kda, kdb = ka/kc, kb/kc
+	}
+
+	return &colorFilter{
+		fn: func(px pixel) pixel {
+			l, a, b := convertRGBToLab(
+				minf32(maxf32(px.r, 0), 1),
+				minf32(maxf32(px.g, 0), 1),
+				minf32(maxf32(px.b, 0), 1),
+			)
+			da, db := a-ka, b-kb
+			d := sqrtf32(da*da + db*db)
+
+			var alpha float32
+			switch {
+			case d <= tol:
+				alpha = 0
+			case d >= tol+soft:
+				alpha = 1
+			default:
+				alpha = (d - tol) / soft
+			}
+			if alpha == 0 {
+				return pixel{px.r, px.g, px.b, 0}
+			}
+
+			if sp > 0 {
+				proj := a*kda + b*kdb
+				if proj > 0 {
+					a -= kda * proj * sp
+					b -= kdb * proj * sp
+					px.r, px.g, px.b = convertLabToRGB(l, a, b)
+				}
+			}
+			px.a *= alpha
+			return px
+		},
+	}
+}
+
+// ColorVisionDeficiency is a type of color vision deficiency (color blindness).
+type ColorVisionDeficiency int
+
+// Color vision deficiency types.
+const (
+	// Protanopia is the absence of the long-wavelength (red) cones.
+	Protanopia ColorVisionDeficiency = iota
+	// Deuteranopia is the absence of the medium-wavelength (green) cones.
+	Deuteranopia
+	// Tritanopia is the absence of the short-wavelength (blue) cones.
+	Tritanopia
+	// Achromatopsia is the total absence of color vision.
+	Achromatopsia
+)
+
+// cvdMatrices are the Machado et al. (2009) simulation matrices for linear RGB at full severity.
+var cvdMatrices = [3][9]float32{
+	Protanopia: {
+		0.152286, 1.052583, -0.204868,
+		0.114503, 0.786281, 0.099216,
+		-0.003882, -0.048116, 1.051998,
+	},
+	Deuteranopia: {
+		0.367322, 0.860646, -0.227968,
+		0.280085, 0.672501, 0.047413,
+		-0.011820, 0.042940, 0.968881,
+	},
+	Tritanopia: {
+		1.255528, -0.076749, -0.178779,
+		-0.078411, 0.930809, 0.147602,
+		0.004733, 0.691367, 0.303900,
+	},
+}
+
+// cvdSimulator returns a function that simulates the color vision deficiency on linear RGB values.
+// The deficiency must be one of the defined types and the severity must be in range (0, 1).
+// Partial severities are interpolated between the normal vision and the full deficiency.
+func cvdSimulator(cvd ColorVisionDeficiency, severity float32) func(r, g, b float32) (float32, float32, float32) {
+	if cvd == Achromatopsia {
+		return func(r, g, b float32) (float32, float32, float32) {
+			y := luminance(r, g, b)
+			return r + (y-r)*severity, g + (y-g)*severity, b + (y-b)*severity
+		}
+	}
+
+	m := cvdMatrices[cvd]
+	identity := [9]float32{1, 0, 0, 0, 1, 0, 0, 0, 1}
+	for i := range m {
+		m[i] = identity[i] + (m[i]-identity[i])*severity
+	}
+	return func(r, g, b float32) (float32, float32, float32) {
+		return m[0]*r + m[1]*g + m[2]*b,
+			m[3]*r + m[4]*g + m[5]*b,
+			m[6]*r + m[7]*g + m[8]*b
+	}
+}
+
+// SimulateColorVisionDeficiency creates a filter that simulates how an image is seen by a person
+// with the given color vision deficiency, using the Machado et al. model in linear RGB.
+// The severity parameter must be in range (0, 100), 100 means the complete absence of the affected cones.
+// If cvd is not one of the defined types, the image is left unchanged.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.SimulateColorVisionDeficiency(gift.Deuteranopia, 100),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func SimulateColorVisionDeficiency(cvd ColorVisionDeficiency, severity float32) Filter {
+	sev := minf32(maxf32(severity, 0), 100) / 100
+	if sev == 0 || cvd < Protanopia || cvd > Achromatopsia {
+		return &copyimageFilter{}
+	}
+
+	simulate := cvdSimulator(cvd, sev)
+
+	return &colorFilter{
+		fn: func(px pixel) pixel {
+			r, g, b := simulate(srgbToLinear(px.r), srgbToLinear(px.g), srgbToLinear(px.b))
+			r = linearToSRGB(minf32(maxf32(r, 0), 1))
+			g = linearToSRGB(minf32(maxf32(g, 0), 1))
+			b = linearToSRGB(minf32(maxf32(b, 0), 1))
+			return pixel{r, g, b, px.a}
+		},
+	}
+}
+
+// Daltonize creates a filter that adjusts the colors of an image to make them more distinguishable
+// for a person with the given color vision deficiency. The color information lost by the deficiency
+// is redistributed to the channels that are still perceived.
+// The severity parameter must be in range (0, 100).
+// Achromatopsia can't be compensated, the image is left unchanged in that case
+// as well as if cvd is not one of the defined types.
+func Daltonize(cvd ColorVisionDeficiency, severity float32) Filter {
+	sev := minf32(maxf32(severity, 0), 100) / 100
+	if sev == 0 || cvd < Protanopia || cvd >= Achromatopsia {
+		return &copyimageFilter{}
+	}
+
+	simulate := cvdSimulator(cvd, sev)
+
+	return &colorFilter{
+		fn: func(px pixel) pixel {
+			r, g, b := srgbToLinear(px.r), srgbToLinear(px.g), srgbToLinear(px.b)
+			sr, sg, sb := simulate(r, g, b)
+			er, eg, eb := r-sr, g-sg, b-sb
+			if cvd == Tritanopia {
+				r += er + 0.7*eb
+				g += eg + 0.7*eb
+			} else {
+				g += 0.7*er + eg
+				b += 0.7*er + eb
+			}
+			r = linearToSRGB(minf32(maxf32(r, 0), 1))
+			g = linearToSRGB(minf32(maxf32(g, 0), 1))
+			b = linearToSRGB(minf32(maxf32(b, 0), 1))
+			return pixel{r, g, b, px.a}
+		},
+	}
+}
+
 // ColorBalance creates a filter that changes the color balance of an image.
 // The percentage parameters for each color channel (red, green, blue) must be in range (-100, 500).
 //
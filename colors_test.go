@@ -1,6 +1,7 @@
 package gift
 
 import (
+	"bytes"
 	"image"
 	"image/color"
 	"image/draw"
@@ -1159,3 +1160,447 @@ func TestColorFunc(t *testing.T) {
 		}
 	}
 }
+
+func TestLab(t *testing.T) {
+	testData := []struct {
+		r, g, b  float32
+		l, a, bl float32
+	}{
+		{0, 0, 0, 0, 0, 0},
+		{1, 1, 1, 100, 0, 0},
+		{1, 0, 0, 53.24, 80.09, 67.20},
+		{0, 1, 0, 87.73, -86.18, 83.18},
+		{0, 0, 1, 32.30, 79.19, -107.86},
+	}
+	for _, d := range testData {
+		l, a, bl := convertRGBToLab(d.r, d.g, d.b)
+		if absf32(l-d.l) > 0.1 || absf32(a-d.a) > 0.1 || absf32(bl-d.bl) > 0.1 {
+			t.Errorf("convertRGBToLab(%v, %v, %v): expected %v %v %v got %v %v %v", d.r, d.g, d.b, d.l, d.a, d.bl, l, a, bl)
+		}
+		r, g, b := convertLabToRGB(l, a, bl)
+		if absf32(r-d.r) > 0.001 || absf32(g-d.g) > 0.001 || absf32(b-d.b) > 0.001 {
+			t.Errorf("convertLabToRGB(%v, %v, %v): expected %v %v %v got %v %v %v", l, a, bl, d.r, d.g, d.b, r, g, b)
+		}
+	}
+}
+
+func TestGradientMap(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"gradient map no stops",
+			GradientMap(nil, GradientSRGB),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+		},
+		{
+			"gradient map empty stops",
+			GradientMap([]GradientStop{}, GradientLinear),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+		},
+		{
+			"gradient map single stop",
+			GradientMap([]GradientStop{{0.5, color.NRGBA{0x10, 0x20, 0x30, 0xff}}}, GradientSRGB),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+			[]uint8{
+				0x10, 0x20, 0x30, 0xff, 0x10, 0x20, 0x30, 0x80,
+			},
+		},
+		{
+			"gradient map nil color",
+			GradientMap([]GradientStop{{0, nil}, {1, color.NRGBA{0xff, 0xff, 0xff, 0xff}}}, GradientSRGB),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"gradient map single nil stop",
+			GradientMap([]GradientStop{{0.5, nil}}, GradientLab),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 1),
+			[]uint8{
+				0x00, 0x40, 0x80, 0xff, 0xff, 0xff, 0xff, 0x80,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			"gradient map srgb",
+			GradientMap(
+				[]GradientStop{
+					{1, color.NRGBA{0xff, 0x00, 0x00, 0xff}},
+					{0, color.NRGBA{0x00, 0x00, 0xff, 0xff}},
+					{0.5, color.NRGBA{0x00, 0xff, 0x00, 0xff}},
+				},
+				GradientSRGB,
+			),
+			image.Rect(-1, -1, 3, 0),
+			image.Rect(0, 0, 4, 1),
+			[]uint8{
+				0x00, 0x00, 0x00, 0xff, 0x40, 0x40, 0x40, 0xff, 0xc0, 0xc0, 0xc0, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			[]uint8{
+				0x00, 0x00, 0xff, 0xff, 0x00, 0x80, 0x7f, 0xff, 0x81, 0x7e, 0x00, 0xff, 0xff, 0x00, 0x00, 0xff,
+			},
+		},
+		{
+			"duotone",
+			Duotone(color.Black, color.White),
+			image.Rect(-1, -1, 3, 0),
+			image.Rect(0, 0, 4, 1),
+			[]uint8{
+				0x00, 0x00, 0x00, 0xff, 0x40, 0x40, 0x40, 0xff, 0xc0, 0xc0, 0xc0, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0xff, 0x40, 0x40, 0x40, 0xff, 0xc0, 0xc0, 0xc0, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewNRGBA(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewNRGBA(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+
+	// Lab interpolation between two colors of equal lightness keeps the lightness.
+	src := image.NewGray(image.Rect(0, 0, 256, 1))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+	c1 := color.NRGBA{0xc0, 0x40, 0x40, 0xff}
+	l1, _, _ := convertRGBToLab(pixelFromColor(c1).r, pixelFromColor(c1).g, pixelFromColor(c1).b)
+	c2 := color.NRGBA{0x40, 0x80, 0xa8, 0xff}
+	l2, _, _ := convertRGBToLab(pixelFromColor(c2).r, pixelFromColor(c2).g, pixelFromColor(c2).b)
+	f := GradientMap([]GradientStop{{0, c1}, {1, c2}}, GradientLab)
+	dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	for x := 0; x < 256; x++ {
+		px := pixelFromColor(dst.At(x, 0))
+		l, _, _ := convertRGBToLab(px.r, px.g, px.b)
+		lmin, lmax := minf32(l1, l2), maxf32(l1, l2)
+		if l < lmin-1 || l > lmax+1 {
+			t.Errorf("gradient map lab: lightness %v out of range (%v, %v) at %d", l, lmin, lmax, x)
+		}
+	}
+}
+
+func TestPosterize(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"posterize (2)",
+			Posterize(2, false),
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x00, 0x33, 0x66, 0x80, 0xcc, 0xff},
+			[]uint8{0x00, 0x00, 0x00, 0xff, 0xff, 0xff},
+		},
+		{
+			"posterize (3)",
+			Posterize(3, false),
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x00, 0x33, 0x66, 0x80, 0xcc, 0xff},
+			[]uint8{0x00, 0x00, 0x80, 0x80, 0xff, 0xff},
+		},
+		{
+			"posterize (1)",
+			Posterize(1, false),
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x00, 0x33, 0x66, 0x80, 0xcc, 0xff},
+			[]uint8{0x00, 0x00, 0x00, 0xff, 0xff, 0xff},
+		},
+		{
+			"posterize (256)",
+			Posterize(256, false),
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x00, 0x33, 0x66, 0x80, 0xcc, 0xff},
+			[]uint8{0x00, 0x33, 0x66, 0x80, 0xcc, 0xff},
+		},
+		{
+			"posterize (2) dither",
+			Posterize(2, true),
+			image.Rect(-1, -1, 3, 3),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x40, 0x40, 0x40, 0x40,
+				0x40, 0x40, 0x40, 0x40,
+				0x40, 0x40, 0x40, 0x40,
+				0x40, 0x40, 0x40, 0x40,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0x00, 0xff, 0x00,
+				0x00, 0x00, 0x00, 0x00,
+				0xff, 0x00, 0xff, 0x00,
+			},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	src.Pix = []uint8{0x40, 0x80, 0xc0, 0x80}
+	f := PosterizeChannels(2, 3, 256, false)
+	dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	want := []uint8{0x00, 0x80, 0xc0, 0x80}
+	if !checkBoundsAndPix(dst.Bounds(), src.Bounds(), dst.Pix, want) {
+		t.Errorf("test [posterize channels] failed: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+}
+
+func TestSelectiveColor(t *testing.T) {
+	testData := []struct {
+		desc           string
+		adj            []SelectiveColorAdjustment
+		relative       bool
+		srcPix, dstPix []uint8
+	}{
+		{
+			"selective color none",
+			nil,
+			false,
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+		},
+		{
+			"selective color reds cyan absolute",
+			[]SelectiveColorAdjustment{{Range: RedsColorRange, Cyan: 50}},
+			false,
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+			[]uint8{0x80, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+		},
+		{
+			"selective color reds cyan relative",
+			[]SelectiveColorAdjustment{{Range: RedsColorRange, Cyan: 50}},
+			true,
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+		},
+		{
+			"selective color reds yellow",
+			[]SelectiveColorAdjustment{{Range: RedsColorRange, Yellow: -100}},
+			false,
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x00, 0xff, 0x00, 0xff},
+			[]uint8{0xff, 0x00, 0xff, 0xff, 0x00, 0xff, 0x00, 0xff},
+		},
+		{
+			"selective color neutrals black",
+			[]SelectiveColorAdjustment{{Range: NeutralsColorRange, Black: 50}},
+			false,
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x80, 0x80, 0x80, 0xff},
+			[]uint8{0xff, 0x00, 0x00, 0xff, 0x01, 0x01, 0x01, 0xff},
+		},
+		{
+			"selective color whites",
+			[]SelectiveColorAdjustment{{Range: WhitesColorRange, Magenta: 100}},
+			false,
+			[]uint8{0xff, 0xff, 0xff, 0xff, 0x40, 0x40, 0x40, 0xff},
+			[]uint8{0xff, 0x00, 0xff, 0xff, 0x40, 0x40, 0x40, 0xff},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+		src.Pix = d.srcPix
+
+		f := SelectiveColor(d.adj, d.relative)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), src.Bounds(), dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestChromaKey(t *testing.T) {
+	key := color.NRGBA{0x00, 0xb0, 0x40, 0xff}
+
+	src := image.NewNRGBA(image.Rect(-1, -1, 3, 0))
+	src.Pix = []uint8{
+		0x00, 0xb0, 0x40, 0xff, // key color
+		0x00, 0x80, 0x30, 0xff, // shaded backdrop
+		0xd0, 0x20, 0x20, 0xff, // red subject
+		0x80, 0xa0, 0x80, 0xff, // greenish gray edge
+	}
+
+	f := ChromaKey(key, 30, 20, 0)
+	dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if !dst.Bounds().Eq(image.Rect(0, 0, 4, 1)) {
+		t.Fatalf("ChromaKey bad bounds: %v", dst.Bounds())
+	}
+	if dst.Pix[3] != 0 || dst.Pix[7] != 0 {
+		t.Errorf("ChromaKey: expected backdrop to be transparent: %#v", dst.Pix)
+	}
+	if !bytes.Equal(dst.Pix[8:12], src.Pix[8:12]) {
+		t.Errorf("ChromaKey: expected subject to be unchanged: %#v", dst.Pix[8:12])
+	}
+	if dst.Pix[15] == 0 {
+		t.Errorf("ChromaKey: expected edge to stay visible: %#v", dst.Pix[12:16])
+	}
+
+	f = ChromaKey(key, 30, 20, 100)
+	dst2 := image.NewNRGBA(f.Bounds(src.Bounds()))
+	f.Draw(dst2, src, nil)
+	if dst2.Pix[15] != dst.Pix[15] {
+		t.Errorf("ChromaKey: spill suppression changed alpha: %#v", dst2.Pix[12:16])
+	}
+	if dst2.Pix[13] >= dst.Pix[13] {
+		t.Errorf("ChromaKey: expected spill suppression to reduce green: %#v", dst2.Pix[12:16])
+	}
+	if absf32(float32(dst2.Pix[8])-float32(src.Pix[8])) > 1 {
+		t.Errorf("ChromaKey: spill suppression changed the subject: %#v", dst2.Pix[8:12])
+	}
+}
+
+func TestSimulateColorVisionDeficiency(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(-1, -1, 3, 0))
+	src.Pix = []uint8{
+		0x80, 0x80, 0x80, 0xff,
+		0xff, 0x00, 0x00, 0xff,
+		0x00, 0xff, 0x00, 0x80,
+		0x20, 0x40, 0xe0, 0xff,
+	}
+
+	for _, cvd := range []ColorVisionDeficiency{Protanopia, Deuteranopia, Tritanopia, Achromatopsia} {
+		f := SimulateColorVisionDeficiency(cvd, 0)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !bytes.Equal(dst.Pix, src.Pix) {
+			t.Errorf("SimulateColorVisionDeficiency(%v, 0): expected unchanged image, got %#v", cvd, dst.Pix)
+		}
+
+		f = SimulateColorVisionDeficiency(cvd, 100)
+		dst = image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !dst.Bounds().Eq(image.Rect(0, 0, 4, 1)) {
+			t.Errorf("SimulateColorVisionDeficiency(%v, 100): bad bounds %v", cvd, dst.Bounds())
+		}
+		for i := 0; i < 3; i++ {
+			if absf32(float32(dst.Pix[i])-0x80) > 1 {
+				t.Errorf("SimulateColorVisionDeficiency(%v, 100): expected gray to be unchanged, got %#v", cvd, dst.Pix[:4])
+				break
+			}
+		}
+		if dst.Pix[11] != 0x80 {
+			t.Errorf("SimulateColorVisionDeficiency(%v, 100): expected alpha to be unchanged, got %#v", cvd, dst.Pix[8:12])
+		}
+		if cvd == Achromatopsia {
+			for i := 0; i < len(dst.Pix); i += 4 {
+				if dst.Pix[i] != dst.Pix[i+1] || dst.Pix[i] != dst.Pix[i+2] {
+					t.Errorf("SimulateColorVisionDeficiency(Achromatopsia, 100): expected gray, got %#v", dst.Pix[i:i+4])
+				}
+			}
+		}
+	}
+
+	// Red and green become hard to tell apart for protanopes and deuteranopes.
+	for _, cvd := range []ColorVisionDeficiency{Protanopia, Deuteranopia} {
+		f := SimulateColorVisionDeficiency(cvd, 100)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		red := pixelFromColor(dst.At(1, 0))
+		green := pixelFromColor(dst.At(2, 0))
+		rh, _, _ := convertRGBToHSL(red.r, red.g, red.b)
+		gh, _, _ := convertRGBToHSL(green.r, green.g, green.b)
+		if absf32(rh-gh) > 0.05 {
+			t.Errorf("SimulateColorVisionDeficiency(%v, 100): red and green are too distinct: %v %v", cvd, red, green)
+		}
+	}
+
+	// Unknown deficiency types leave the image unchanged.
+	for _, cvd := range []ColorVisionDeficiency{-1, 9} {
+		f := SimulateColorVisionDeficiency(cvd, 50)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !bytes.Equal(dst.Pix, src.Pix) {
+			t.Errorf("SimulateColorVisionDeficiency(%v, 50): expected unchanged image, got %#v", cvd, dst.Pix)
+		}
+	}
+}
+
+func TestDaltonize(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Pix = []uint8{
+		0x80, 0x80, 0x80, 0xff,
+		0xc0, 0x40, 0x20, 0xff,
+	}
+
+	for _, cvd := range []ColorVisionDeficiency{Protanopia, Deuteranopia, Tritanopia, Achromatopsia} {
+		f := Daltonize(cvd, 100)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		for i := 0; i < 4; i++ {
+			if absf32(float32(dst.Pix[i])-float32(src.Pix[i])) > 1 {
+				t.Errorf("Daltonize(%v, 100): expected gray to be unchanged, got %#v", cvd, dst.Pix[:4])
+				break
+			}
+		}
+		changed := !bytes.Equal(dst.Pix[4:], src.Pix[4:])
+		if changed != (cvd != Achromatopsia) {
+			t.Errorf("Daltonize(%v, 100): unexpected result %#v", cvd, dst.Pix[4:])
+		}
+	}
+
+	for _, cvd := range []ColorVisionDeficiency{-1, 9} {
+		f := Daltonize(cvd, 50)
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !bytes.Equal(dst.Pix, src.Pix) {
+			t.Errorf("Daltonize(%v, 50): expected unchanged image, got %#v", cvd, dst.Pix)
+		}
+	}
+}
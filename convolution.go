@@ -102,22 +102,48 @@ func (p *convolutionFilter) Draw(dst draw.Image, src image.Image, options *Optio
 		return
 	}
 
+	// Smoothing kernels weight the colors by alpha when the alpha channel is filtered,
+	// otherwise the colors of transparent pixels would bleed into the result.
+	smoothing := true
+	for _, w := range weights {
+		if w.weight < 0 {
+			smoothing = false
+			break
+		}
+	}
+	premultiply := p.alpha && smoothing
+	linear := options.LinearLight && smoothing
+
 	pixGetter := newPixelGetter(src)
 	pixSetter := newPixelSetter(dst)
 
+	borderpx := []pixel{borderPixel(options.BorderColor)}
+	if linear {
+		pixelsToLinear(borderpx)
+	}
+
+	// loadRow loads the src row y, the rows beyond the image bounds are extended according to the border mode.
+	loadRow := func(y int, row *[]pixel) {
+		i, ok := borderIndex(y-srcb.Min.Y, srcb.Dy(), options.BorderMode)
+		if !ok {
+			for j := range *row {
+				(*row)[j] = borderpx[0]
+			}
+			return
+		}
+		pixGetter.getPixelRow(srcb.Min.Y+i, row)
+		if linear {
+			pixelsToLinear(*row)
+		}
+	}
+
 	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
 		// Init temporary rows.
 		starty := start
 		rows := make([][]pixel, ksize)
 		for i := 0; i < ksize; i++ {
-			rowy := starty + i - kcenter
-			if rowy < srcb.Min.Y {
-				rowy = srcb.Min.Y
-			} else if rowy > srcb.Max.Y-1 {
-				rowy = srcb.Max.Y - 1
-			}
 			row := make([]pixel, srcb.Dx())
-			pixGetter.getPixelRow(rowy, &row)
+			loadRow(starty+i-kcenter, &row)
 			rows[i] = row
 		}
 
@@ -126,23 +152,35 @@ func (p *convolutionFilter) Draw(dst draw.Image, src image.Image, options *Optio
 			for x := srcb.Min.X; x < srcb.Max.X; x++ {
 				var r, g, b, a float32
 				for _, w := range weights {
-					wx := x + w.u
-					if wx < srcb.Min.X {
-						wx = srcb.Min.X
-					} else if wx > srcb.Max.X-1 {
-						wx = srcb.Max.X - 1
+					var px pixel
+					// Only the taps beyond the image bounds need the border mode.
+					if rowsx := x + w.u - srcb.Min.X; rowsx >= 0 && rowsx < srcb.Dx() {
+						px = rows[kcenter+w.v][rowsx]
+					} else if rowsx, ok := borderIndex(rowsx, srcb.Dx(), options.BorderMode); ok {
+						px = rows[kcenter+w.v][rowsx]
+					} else {
+						px = borderpx[0]
 					}
-					rowsx := wx - srcb.Min.X
-					rowsy := kcenter + w.v
-
-					px := rows[rowsy][rowsx]
-					r += px.r * w.weight
-					g += px.g * w.weight
-					b += px.b * w.weight
-					if p.alpha {
-						a += px.a * w.weight
+					if premultiply {
+						wa := px.a * w.weight
+						r += px.r * wa
+						g += px.g * wa
+						b += px.b * wa
+						a += wa
+					} else {
+						r += px.r * w.weight
+						g += px.g * w.weight
+						b += px.b * w.weight
+						if p.alpha {
+							a += px.a * w.weight
+						}
 					}
 				}
+				if premultiply && a != 0 {
+					r /= a
+					g /= a
+					b /= a
+				}
 				if p.abs {
 					r = absf32(r)
 					g = absf32(g)
@@ -162,6 +200,11 @@ func (p *convolutionFilter) Draw(dst draw.Image, src image.Image, options *Optio
 				if !p.alpha {
 					a = rows[kcenter][x-srcb.Min.X].a
 				}
+				if linear {
+					r = linearToSRGB(r)
+					g = linearToSRGB(g)
+					b = linearToSRGB(b)
+				}
 				pixSetter.setPixel(dstb.Min.X+x-srcb.Min.X, dstb.Min.Y+y-srcb.Min.Y, pixel{r, g, b, a})
 			}
 
@@ -171,11 +214,7 @@ func (p *convolutionFilter) Draw(dst draw.Image, src image.Image, options *Optio
 				for i := 0; i < ksize-1; i++ {
 					rows[i] = rows[i+1]
 				}
-				nextrowy := y + ksize/2 + 1
-				if nextrowy > srcb.Max.Y-1 {
-					nextrowy = srcb.Max.Y - 1
-				}
-				pixGetter.getPixelRow(nextrowy, &tmprow)
+				loadRow(y+ksize/2+1, &tmprow)
 				rows[ksize-1] = tmprow
 			}
 		}
@@ -187,6 +226,8 @@ func (p *convolutionFilter) Draw(dst draw.Image, src image.Image, options *Optio
 // Excessive slice members will be ignored.
 // If normalize parameter is true, the kernel will be normalized before applying the filter.
 // If alpha parameter is true, the alpha component of color will be filtered too.
+// In this case, if the kernel has no negative values, the color components are weighted by alpha
+// to avoid dark fringes around transparent areas.
 // If abs parameter is true, absolute values of color components will be taken after doing calculations.
 // If delta parameter is not zero, this value will be added to the filtered pixels.
 //
@@ -241,21 +282,23 @@ func prepareConvolutionWeights1d(kernel []float32) (int, []uweight) {
 }
 
 // convolveLine convolves a single line of pixels according to the given weights.
-func convolveLine(dstBuf []pixel, srcBuf []pixel, weights []uweight) {
-	max := len(srcBuf) - 1
-	if max < 0 {
+// The line is extended beyond its ends according to the border mode, borderpx is used in ConstantBorderMode.
+func convolveLine(dstBuf []pixel, srcBuf []pixel, weights []uweight, border BorderMode, borderpx pixel) {
+	n := len(srcBuf)
+	if n == 0 {
 		return
 	}
-	for dstu := 0; dstu < len(srcBuf); dstu++ {
+	for dstu := 0; dstu < n; dstu++ {
 		var r, g, b, a float32
 		for _, w := range weights {
-			k := dstu + w.u
-			if k < 0 {
-				k = 0
-			} else if k > max {
-				k = max
+			var c pixel
+			if k := dstu + w.u; k >= 0 && k < n {
+				c = srcBuf[k]
+			} else if k, ok := borderIndex(k, n, border); ok {
+				c = srcBuf[k]
+			} else {
+				c = borderpx
 			}
-			c := srcBuf[k]
 			wa := c.a * w.weight
 			r += c.r * wa
 			g += c.g * wa
@@ -285,12 +328,22 @@ func convolve1dv(dst draw.Image, src image.Image, kernel []float32, options *Opt
 	_, weights := prepareConvolutionWeights1d(kernel)
 	pixGetter := newPixelGetter(src)
 	pixSetter := newPixelSetter(dst)
+	borderpx := []pixel{borderPixel(options.BorderColor)}
+	if options.LinearLight {
+		pixelsToLinear(borderpx)
+	}
 	parallelize(options.Parallelization, srcb.Min.X, srcb.Max.X, func(start, stop int) {
 		srcBuf := make([]pixel, srcb.Dy())
 		dstBuf := make([]pixel, srcb.Dy())
 		for x := start; x < stop; x++ {
 			pixGetter.getPixelColumn(x, &srcBuf)
-			convolveLine(dstBuf, srcBuf, weights)
+			if options.LinearLight {
+				pixelsToLinear(srcBuf)
+			}
+			convolveLine(dstBuf, srcBuf, weights, options.BorderMode, borderpx[0])
+			if options.LinearLight {
+				pixelsToSRGB(dstBuf)
+			}
 			pixSetter.setPixelColumn(dstb.Min.X+x-srcb.Min.X, dstBuf)
 		}
 	})
@@ -310,12 +363,22 @@ func convolve1dh(dst draw.Image, src image.Image, kernel []float32, options *Opt
 	_, weights := prepareConvolutionWeights1d(kernel)
 	pixGetter := newPixelGetter(src)
 	pixSetter := newPixelSetter(dst)
+	borderpx := []pixel{borderPixel(options.BorderColor)}
+	if options.LinearLight {
+		pixelsToLinear(borderpx)
+	}
 	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
 		srcBuf := make([]pixel, srcb.Dx())
 		dstBuf := make([]pixel, srcb.Dx())
 		for y := start; y < stop; y++ {
 			pixGetter.getPixelRow(y, &srcBuf)
-			convolveLine(dstBuf, srcBuf, weights)
+			if options.LinearLight {
+				pixelsToLinear(srcBuf)
+			}
+			convolveLine(dstBuf, srcBuf, weights, options.BorderMode, borderpx[0])
+			if options.LinearLight {
+				pixelsToSRGB(dstBuf)
+			}
 			pixSetter.setPixelRow(dstb.Min.Y+y-srcb.Min.Y, dstBuf)
 		}
 	})
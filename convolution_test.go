@@ -90,7 +90,7 @@ func TestConvolution(t *testing.T) {
 			},
 			[]uint8{
 				0x00, 0x00, 0x00, 0x00, 0x20, 0x40, 0x60, 0x80, 0x20, 0x40, 0x60, 0x80,
-				0x80, 0x60, 0x40, 0x20, 0xA0, 0xA0, 0xA0, 0xA0, 0x20, 0x40, 0x60, 0x80,
+				0x80, 0x60, 0x40, 0x20, 0x33, 0x46, 0x5A, 0xA0, 0x20, 0x40, 0x60, 0x80,
 				0x80, 0x60, 0x40, 0x20, 0x80, 0x60, 0x40, 0x20, 0x00, 0x00, 0x00, 0x00,
 			},
 		},
@@ -132,7 +132,7 @@ func TestConvolution(t *testing.T) {
 			},
 			[]uint8{
 				0x03, 0x03, 0x03, 0x03, 0x23, 0x43, 0x63, 0x83, 0x23, 0x43, 0x63, 0x83,
-				0x83, 0x63, 0x43, 0x23, 0xA3, 0xA3, 0xA3, 0xA3, 0x23, 0x43, 0x63, 0x83,
+				0x83, 0x63, 0x43, 0x23, 0x36, 0x49, 0x5D, 0xA3, 0x23, 0x43, 0x63, 0x83,
 				0x83, 0x63, 0x43, 0x23, 0x83, 0x63, 0x43, 0x23, 0x03, 0x03, 0x03, 0x03,
 			},
 		},
@@ -177,9 +177,9 @@ func TestConvolution(t *testing.T) {
 				0x80, 0x60, 0x40, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
 			},
 			[]uint8{
-				0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0,
-				0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0,
-				0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0, 0xA0,
+				0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0,
+				0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0,
+				0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0, 0x33, 0x46, 0x5A, 0xA0,
 			},
 		},
 	}
@@ -222,7 +222,7 @@ func TestConvolution(t *testing.T) {
 	convolve1dv(image.NewGray(image.Rect(0, 0, 1, 1)), image.NewGray(image.Rect(0, 0, 1, 1)), []float32{}, nil)
 	convolve1dh(image.NewGray(image.Rect(0, 0, 0, 0)), image.NewGray(image.Rect(0, 0, 0, 0)), []float32{}, nil)
 	convolve1dv(image.NewGray(image.Rect(0, 0, 0, 0)), image.NewGray(image.Rect(0, 0, 0, 0)), []float32{}, nil)
-	convolveLine([]pixel{}, []pixel{}, []uweight{})
+	convolveLine([]pixel{}, []pixel{}, []uweight{}, ClampBorderMode, pixel{})
 	prepareConvolutionWeights1d([]float32{0, 0})
 	prepareConvolutionWeights1d([]float32{})
 }
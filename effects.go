@@ -3,8 +3,34 @@ package gift
 import (
 	"image"
 	"image/draw"
+	"math"
 )
 
+// Channel is a color channel of an image.
+type Channel int
+
+// Color channels.
+const (
+	RedChannel Channel = iota
+	GreenChannel
+	BlueChannel
+	AlphaChannel
+)
+
+// channelValue returns the value of the given channel of the pixel.
+func channelValue(px pixel, c Channel) float32 {
+	switch c {
+	case GreenChannel:
+		return px.g
+	case BlueChannel:
+		return px.b
+	case AlphaChannel:
+		return px.a
+	default:
+		return px.r
+	}
+}
+
 type pixelateFilter struct {
 	size int
 }
@@ -85,3 +111,247 @@ func Pixelate(size int) Filter {
 		size: size,
 	}
 }
+
+type displaceFilter struct {
+	dmap               image.Image
+	scaleX, scaleY     float32
+	channelX, channelY Channel
+	interpolation      Interpolation
+}
+
+func (p *displaceFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+	return
+}
+
+func (p *displaceFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 {
+		return
+	}
+	if p.dmap == nil {
+		copyimage(dst, src, options)
+		return
+	}
+
+	mapb := p.dmap.Bounds()
+	mapGetter := newPixelGetter(p.dmap)
+
+	warp(dst, src, srcb.Dx(), srcb.Dy(), func(x, y float32) (float32, float32, bool) {
+		xf, yf := float32(srcb.Min.X)+x, float32(srcb.Min.Y)+y
+		mx, my := mapb.Min.X+int(x), mapb.Min.Y+int(y)
+		if image.Pt(mx, my).In(mapb) {
+			px := mapGetter.getPixel(mx, my)
+			xf += p.scaleX * (channelValue(px, p.channelX) - 0.5)
+			yf += p.scaleY * (channelValue(px, p.channelY) - 0.5)
+		}
+		return xf, yf, true
+	}, interpolator{interpolation: p.interpolation}, pixel{}, options)
+}
+
+// Displace creates a filter that moves the pixels of an image according to a displacement map,
+// like the SVG feDisplacementMap filter primitive. Each pixel of the result is taken from the src image
+// at the position offset by scaleX * (X - 0.5) horizontally and by scaleY * (Y - 0.5) vertically,
+// where X and Y are the values (in the range 0 to 1) of the channelX and channelY channels
+// of the corresponding displacement map pixel. The map is aligned with the top-left corner of the src image,
+// the pixels not covered by the map are not displaced.
+// The interpolation parameter specifies the interpolation method. The pixels taken from beyond the src image
+// are transparent unless the border mode extends the image (see GIFT.SetBorderMode).
+//
+// Example:
+//
+//	// Apply a heat haze effect using a noise texture.
+//	g := gift.New(
+//		gift.Displace(noise, 8, 4, gift.RedChannel, gift.GreenChannel, gift.LinearInterpolation),
+//	)
+//	g.SetBorderMode(gift.ReflectBorderMode)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Displace(displacementMap image.Image, scaleX, scaleY float32, channelX, channelY Channel, interpolation Interpolation) Filter {
+	return &displaceFilter{
+		dmap:          displacementMap,
+		scaleX:        scaleX,
+		scaleY:        scaleY,
+		channelX:      channelX,
+		channelY:      channelY,
+		interpolation: interpolation,
+	}
+}
+
+// distortionFilter is a geometric distortion that keeps the image size.
+// The mapping function receives the coordinates of a dst point relative to the image center
+// and the image size, and returns the relative coordinates of the corresponding src point.
+type distortionFilter struct {
+	mapping       func(x, y, w, h float32) (float32, float32)
+	interpolation Interpolation
+}
+
+func (p *distortionFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+	return
+}
+
+func (p *distortionFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 {
+		return
+	}
+
+	w, h := float32(srcb.Dx()), float32(srcb.Dy())
+	cx, cy := float32(srcb.Min.X)+w/2, float32(srcb.Min.Y)+h/2
+
+	warp(dst, src, srcb.Dx(), srcb.Dy(), func(x, y float32) (float32, float32, bool) {
+		sx, sy := p.mapping(x+0.5-w/2, y+0.5-h/2, w, h)
+		return cx + sx - 0.5, cy + sy - 0.5, true
+	}, interpolator{interpolation: p.interpolation}, pixel{}, options)
+}
+
+// effectRadius returns the radius of a circular effect, the default is half of the smaller image dimension.
+func effectRadius(radius, w, h float32) float32 {
+	if radius > 0 {
+		return radius
+	}
+	return minf32(w, h) / 2
+}
+
+// Swirl creates a filter that rotates the image around its center by the angle decreasing
+// from the given angle at the center to zero at the given radius.
+// The angle parameter is in degrees, positive values swirl the image counter-clockwise.
+// If the radius is not positive, half of the smaller image dimension is used.
+// The pixels taken from beyond the src image are transparent unless the border mode extends the image.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.Swirl(120, 0, gift.LinearInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Swirl(angle, radius float32, interpolation Interpolation) Filter {
+	return &distortionFilter{
+		mapping: func(x, y, w, h float32) (float32, float32) {
+			r := effectRadius(radius, w, h)
+			d := sqrtf32(x*x + y*y)
+			if d >= r {
+				return x, y
+			}
+			k := 1 - d/r
+			asin, acos := sincosf32(angle * k * k)
+			return x*acos - y*asin, x*asin + y*acos
+		},
+		interpolation: interpolation,
+	}
+}
+
+// Wave creates a filter that distorts the image with a sine wave.
+// The pixels are shifted by up to amplitude pixels perpendicular to the direction of the wave propagation.
+// The wavelength parameter is the wave period in pixels.
+// The direction parameter is the angle in degrees between the wave propagation direction and the x axis
+// measured counter-clockwise, e.g. 0 makes the horizontal lines wavy.
+// The pixels taken from beyond the src image are transparent unless the border mode extends the image.
+// The interpolation parameter specifies the interpolation method.
+func Wave(amplitude, wavelength, direction float32, interpolation Interpolation) Filter {
+	dsin, dcos := sincosf32(direction)
+	return &distortionFilter{
+		mapping: func(x, y, w, h float32) (float32, float32) {
+			if wavelength == 0 {
+				return x, y
+			}
+			// The distance along the propagation direction (y axis points down).
+			s := x*dcos - y*dsin
+			offset := amplitude * float32(math.Sin(2*math.Pi*float64(s/wavelength)))
+			// Shift along the normal to the propagation direction.
+			return x - offset*dsin, y - offset*dcos
+		},
+		interpolation: interpolation,
+	}
+}
+
+// Ripple creates a filter that distorts the image with concentric circular waves around its center,
+// like the ripples on a water surface. The pixels are shifted by up to amplitude pixels towards or away from the center.
+// The wavelength parameter is the distance between the ripples in pixels.
+// The pixels taken from beyond the src image are transparent unless the border mode extends the image.
+// The interpolation parameter specifies the interpolation method.
+func Ripple(amplitude, wavelength float32, interpolation Interpolation) Filter {
+	return &distortionFilter{
+		mapping: func(x, y, w, h float32) (float32, float32) {
+			d := sqrtf32(x*x + y*y)
+			if wavelength == 0 || d == 0 {
+				return x, y
+			}
+			offset := amplitude * float32(math.Sin(2*math.Pi*float64(d/wavelength)))
+			k := (d + offset) / d
+			return x * k, y * k
+		},
+		interpolation: interpolation,
+	}
+}
+
+// Pinch creates a filter that squeezes the area within the given radius from the image center towards the center.
+// The amount parameter must be in the range [-1, 1], negative values create a bulge instead (see Bulge).
+// If the radius is not positive, half of the smaller image dimension is used.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.Pinch(0.5, 100, gift.CubicInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Pinch(amount, radius float32, interpolation Interpolation) Filter {
+	amount = minf32(maxf32(amount, -1), 1)
+	return &distortionFilter{
+		mapping: func(x, y, w, h float32) (float32, float32) {
+			r := effectRadius(radius, w, h)
+			d := sqrtf32(x*x + y*y)
+			if d >= r || d == 0 || amount == 0 {
+				return x, y
+			}
+			k := float32(math.Pow(math.Sin(math.Pi/2*float64(d/r)), float64(-amount)))
+			return x * k, y * k
+		},
+		interpolation: interpolation,
+	}
+}
+
+// Bulge creates a filter that magnifies the area within the given radius from the image center.
+// The amount parameter must be in the range [-1, 1]. Bulge(amount, radius) is the same as Pinch(-amount, radius).
+// If the radius is not positive, half of the smaller image dimension is used.
+// The interpolation parameter specifies the interpolation method.
+func Bulge(amount, radius float32, interpolation Interpolation) Filter {
+	return Pinch(-amount, radius, interpolation)
+}
+
+// Fisheye creates a filter that simulates a fisheye lens: the image center is magnified
+// and the periphery is compressed while the corners stay in place.
+// The strength parameter is typically in the range [0, 1], 0 leaves the image unchanged.
+// Negative values (down to -0.9) create the opposite effect.
+// The interpolation parameter specifies the interpolation method.
+func Fisheye(strength float32, interpolation Interpolation) Filter {
+	strength = maxf32(strength, -0.9)
+	return &distortionFilter{
+		mapping: func(x, y, w, h float32) (float32, float32) {
+			r := sqrtf32(w*w+h*h) / 2
+			d := sqrtf32(x*x + y*y)
+			if d == 0 || strength == 0 {
+				return x, y
+			}
+			k := float32(math.Pow(float64(d/r), float64(strength)))
+			return x * k, y * k
+		},
+		interpolation: interpolation,
+	}
+}
@@ -2,6 +2,7 @@ package gift
 
 import (
 	"image"
+	"image/color"
 	"testing"
 )
 
@@ -115,3 +116,261 @@ func TestPixelate(t *testing.T) {
 		}
 	}
 }
+
+func TestDisplace(t *testing.T) {
+	newMap := func(w, h int, c color.Color) image.Image {
+		img := image.NewNRGBA(image.Rect(-5, -5, w-5, h-5))
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				img.Set(x, y, c)
+			}
+		}
+		return img
+	}
+	right := newMap(4, 1, color.NRGBA{0xff, 0x00, 0x80, 0xff})
+
+	testData := []struct {
+		desc           string
+		f              Filter
+		mode           BorderMode
+		srcb           image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"displace right",
+			Displace(right, 2, 0, RedChannel, BlueChannel, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(-1, 0, 3, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x20, 0x30, 0x40, 0x00},
+		},
+		{
+			"displace right wrap",
+			Displace(right, 2, 0, RedChannel, BlueChannel, NearestNeighborInterpolation),
+			WrapBorderMode,
+			image.Rect(-1, 0, 3, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x20, 0x30, 0x40, 0x10},
+		},
+		{
+			"displace left",
+			Displace(right, 2, 0, GreenChannel, BlueChannel, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(0, 0, 4, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x00, 0x10, 0x20, 0x30},
+		},
+		{
+			"displace half pixel linear",
+			Displace(right, 1, 0, AlphaChannel, BlueChannel, LinearInterpolation),
+			Reflect101BorderMode,
+			image.Rect(0, 0, 4, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x18, 0x28, 0x38, 0x38},
+		},
+		{
+			"displace small map",
+			Displace(newMap(2, 1, color.White), 2, 0, RedChannel, RedChannel, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(0, 0, 4, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x20, 0x30, 0x30, 0x40},
+		},
+		{
+			"displace vertical",
+			Displace(newMap(1, 3, color.Black), 0, 2, RedChannel, BlueChannel, NearestNeighborInterpolation),
+			ReflectBorderMode,
+			image.Rect(0, 0, 1, 3),
+			[]uint8{0x10, 0x20, 0x30},
+			[]uint8{0x10, 0x10, 0x20},
+		},
+		{
+			"displace nil map",
+			Displace(nil, 2, 2, RedChannel, GreenChannel, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(0, 0, 4, 1),
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+			[]uint8{0x10, 0x20, 0x30, 0x40},
+		},
+		{
+			"displace 0x0",
+			Displace(right, 2, 2, RedChannel, GreenChannel, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, &Options{BorderMode: d.mode})
+
+		if !checkBoundsAndPix(dst.Bounds(), d.srcb.Sub(d.srcb.Min), dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestDistortions(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		mode           BorderMode
+		srcb           image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"wave",
+			Wave(1, 4, 90, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(-1, 2, 3, 5),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0x09, 0x0a, 0x0b, 0x0c,
+			},
+			[]uint8{
+				0x00, 0x01, 0x02, 0x03,
+				0x05, 0x06, 0x07, 0x08,
+				0x0a, 0x0b, 0x0c, 0x00,
+			},
+		},
+		{
+			"wave wrap",
+			Wave(1, 4, 90, NearestNeighborInterpolation),
+			WrapBorderMode,
+			image.Rect(0, 0, 4, 3),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0x09, 0x0a, 0x0b, 0x0c,
+			},
+			[]uint8{
+				0x04, 0x01, 0x02, 0x03,
+				0x05, 0x06, 0x07, 0x08,
+				0x0a, 0x0b, 0x0c, 0x09,
+			},
+		},
+		{
+			"wave horizontal",
+			Wave(1, 4, 0, NearestNeighborInterpolation),
+			ClampBorderMode,
+			image.Rect(0, 0, 3, 3),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09,
+			},
+			[]uint8{
+				0x04, 0x02, 0x00,
+				0x07, 0x05, 0x03,
+				0x00, 0x08, 0x06,
+			},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, &Options{BorderMode: d.mode})
+
+		if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, d.srcb.Dx(), d.srcb.Dy()), dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestDistortionPoints(t *testing.T) {
+	// A 5x5 image with unique pixel values, the center is at (2.5, 2.5).
+	src := image.NewGray(image.Rect(0, 0, 5, 5))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i + 1)
+	}
+
+	testData := []struct {
+		desc   string
+		f      Filter
+		points [][4]int // The dst pixel (x, y) and the src pixel (x, y) it is taken from.
+	}{
+		{
+			"swirl",
+			Swirl(360, 2, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {3, 2, 2, 3}, {2, 3, 1, 2}, {1, 2, 2, 1}, {2, 1, 3, 2}, {2, 0, 2, 0}, {4, 2, 4, 2}, {0, 0, 0, 0}},
+		},
+		{
+			"swirl clockwise",
+			Swirl(-360, 2, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {3, 2, 2, 1}, {2, 3, 3, 2}, {0, 4, 0, 4}},
+		},
+		{
+			"swirl default radius",
+			Swirl(360, 0, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {0, 0, 0, 0}, {4, 4, 4, 4}},
+		},
+		{
+			"ripple",
+			Ripple(1, 4, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {3, 2, 4, 2}, {2, 1, 2, 0}, {4, 2, 4, 2}, {2, 0, 2, 0}},
+		},
+		{
+			"pinch",
+			Pinch(1, 3, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {3, 2, 4, 2}, {1, 2, 0, 2}, {2, 3, 2, 4}},
+		},
+		{
+			"bulge",
+			Bulge(1, 4, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {3, 2, 2, 2}, {4, 2, 3, 2}, {0, 2, 1, 2}, {2, 4, 2, 3}},
+		},
+		{
+			"fisheye",
+			Fisheye(1, NearestNeighborInterpolation),
+			[][4]int{{2, 2, 2, 2}, {4, 2, 3, 2}, {2, 0, 2, 1}, {0, 0, 0, 0}, {4, 4, 4, 4}},
+		},
+	}
+
+	for _, d := range testData {
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+		if !dst.Bounds().Eq(src.Bounds()) {
+			t.Errorf("test [%s]: unexpected bounds %v", d.desc, dst.Bounds())
+			continue
+		}
+		for _, p := range d.points {
+			want := src.GrayAt(p[2], p[3]).Y
+			if got := dst.GrayAt(p[0], p[1]).Y; got != want {
+				t.Errorf("test [%s]: pixel (%d, %d): expected %#x got %#x", d.desc, p[0], p[1], want, got)
+			}
+		}
+	}
+
+	// The zero parameters leave the image unchanged.
+	for _, f := range []Filter{
+		Swirl(0, 0, LinearInterpolation),
+		Wave(0, 10, 30, LinearInterpolation),
+		Wave(5, 0, 30, LinearInterpolation),
+		Ripple(0, 10, LinearInterpolation),
+		Pinch(0, 0, LinearInterpolation),
+		Bulge(0, 0, LinearInterpolation),
+		Fisheye(0, LinearInterpolation),
+	} {
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !checkBoundsAndPix(dst.Bounds(), src.Bounds(), dst.Pix, src.Pix) {
+			t.Errorf("expected unchanged image: %#v", dst.Pix)
+		}
+	}
+
+	// An empty image.
+	f := Swirl(90, 0, LinearInterpolation)
+	if b := f.Bounds(image.Rect(0, 0, 0, 5)); !b.Empty() {
+		t.Errorf("unexpected bounds %v", b)
+	}
+	f.Draw(image.NewGray(image.Rect(0, 0, 0, 0)), image.NewGray(image.Rect(0, 0, 0, 5)), nil)
+}
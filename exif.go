@@ -0,0 +1,136 @@
+package gift
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+var errInvalidEXIF = errors.New("gift: invalid EXIF data")
+
+// ReadOrientation reads the EXIF orientation tag value of a JPEG or TIFF image from r.
+// Only the beginning of the image is read, up to the EXIF data.
+// It returns 1 (the normal orientation) if the image has no orientation tag or the tag value is not in the range [1, 8].
+// The returned value can be passed to the Orientation filter.
+func ReadOrientation(r io.Reader) (int, error) {
+	var magic [2]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return 0, err
+	}
+	switch string(magic[:]) {
+	case "\xff\xd8":
+		return readJPEGOrientation(r)
+	case "II", "MM":
+		return readTIFFOrientation(io.MultiReader(bytes.NewReader(magic[:]), r))
+	}
+	return 0, errors.New("gift: unsupported image format")
+}
+
+// readJPEGOrientation reads the orientation from the EXIF data in the APP1 segment.
+// The reader is positioned after the SOI marker.
+func readJPEGOrientation(r io.Reader) (int, error) {
+	var buf [2]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:1]); err != nil {
+			return 0, err
+		}
+		if buf[0] != 0xff {
+			return 0, errors.New("gift: invalid JPEG data")
+		}
+		// Skip the fill bytes.
+		marker := buf[0]
+		for marker == 0xff {
+			if _, err := io.ReadFull(r, buf[:1]); err != nil {
+				return 0, err
+			}
+			marker = buf[0]
+		}
+
+		switch {
+		case marker == 0xd9 || marker == 0xda:
+			// The EXIF data must precede the image data.
+			return 1, nil
+		case marker == 0x01 || marker >= 0xd0 && marker <= 0xd7:
+			// The standalone markers have no segment.
+			continue
+		}
+
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, err
+		}
+		n := int64(binary.BigEndian.Uint16(buf[:])) - 2
+		if n < 0 {
+			return 0, errors.New("gift: invalid JPEG data")
+		}
+
+		if marker == 0xe1 {
+			seg := make([]byte, n)
+			if _, err := io.ReadFull(r, seg); err != nil {
+				return 0, err
+			}
+			if bytes.HasPrefix(seg, []byte("Exif\x00\x00")) {
+				return readTIFFOrientation(bytes.NewReader(seg[6:]))
+			}
+			continue
+		}
+		if _, err := io.CopyN(io.Discard, r, n); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// readTIFFOrientation reads the orientation from the first IFD of the TIFF structure.
+// The reader is positioned at the TIFF header.
+func readTIFFOrientation(r io.Reader) (int, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	var order binary.ByteOrder
+	switch string(header[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return 0, errInvalidEXIF
+	}
+
+	// The first IFD usually follows the header, it can't precede it.
+	offset := int64(order.Uint32(header[4:]))
+	if offset < 8 {
+		return 0, errInvalidEXIF
+	}
+	if _, err := io.CopyN(io.Discard, r, offset-8); err != nil {
+		return 0, err
+	}
+
+	var count [2]byte
+	if _, err := io.ReadFull(r, count[:]); err != nil {
+		return 0, err
+	}
+	var entry [12]byte
+	for i := 0; i < int(order.Uint16(count[:])); i++ {
+		if _, err := io.ReadFull(r, entry[:]); err != nil {
+			return 0, err
+		}
+		const (
+			orientationTag = 0x0112
+			shortType      = 3
+		)
+		if order.Uint16(entry[0:]) != orientationTag {
+			continue
+		}
+		// The value is a single SHORT stored in the first bytes of the value field.
+		if order.Uint16(entry[2:]) != shortType {
+			return 1, nil
+		}
+		orientation := int(order.Uint16(entry[8:]))
+		if orientation < 1 || orientation > 8 {
+			return 1, nil
+		}
+		return orientation, nil
+	}
+	return 1, nil
+}
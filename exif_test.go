@@ -0,0 +1,83 @@
+package gift
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// makeTIFF creates a TIFF header with the first IFD containing the given tag.
+func makeTIFF(order binary.ByteOrder, gap int, tag, typ, value uint16) []byte {
+	var buf bytes.Buffer
+	if order == binary.LittleEndian {
+		buf.WriteString("II*\x00")
+	} else {
+		buf.WriteString("MM\x00*")
+	}
+	binary.Write(&buf, order, uint32(8+gap))
+	buf.Write(make([]byte, gap))
+	binary.Write(&buf, order, uint16(2))
+	// An unrelated tag (ImageWidth) goes first.
+	binary.Write(&buf, order, []uint16{0x0100, 3, 0, 1, 640, 0})
+	binary.Write(&buf, order, []uint16{tag, typ, 0, 1, value, 0})
+	binary.Write(&buf, order, uint32(0))
+	return buf.Bytes()
+}
+
+// makeJPEG creates the beginning of a JPEG file with the given APP1 payload.
+func makeJPEG(app1 []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\xff\xd8")
+	// An APP0 segment.
+	buf.WriteString("\xff\xe0\x00\x10JFIF\x00\x01\x01\x00\x00\x01\x00\x01\x00\x00")
+	if app1 != nil {
+		buf.WriteString("\xff\xff\xe1")
+		binary.Write(&buf, binary.BigEndian, uint16(len(app1)+2))
+		buf.Write(app1)
+	}
+	// A DQT segment and the start of the image data.
+	buf.WriteString("\xff\xdb\x00\x04\x00\x00")
+	buf.WriteString("\xff\xda\x00\x02")
+	return buf.Bytes()
+}
+
+func TestReadOrientation(t *testing.T) {
+	exif := func(tiff []byte) []byte {
+		return append([]byte("Exif\x00\x00"), tiff...)
+	}
+
+	testData := []struct {
+		desc string
+		data []byte
+		want int
+		err  bool
+	}{
+		{"tiff little endian", makeTIFF(binary.LittleEndian, 0, 0x0112, 3, 6), 6, false},
+		{"tiff big endian", makeTIFF(binary.BigEndian, 0, 0x0112, 3, 8), 8, false},
+		{"tiff ifd offset", makeTIFF(binary.BigEndian, 5, 0x0112, 3, 3), 3, false},
+		{"tiff no orientation", makeTIFF(binary.LittleEndian, 0, 0x0101, 3, 6), 1, false},
+		{"tiff invalid value", makeTIFF(binary.LittleEndian, 0, 0x0112, 3, 9), 1, false},
+		{"tiff invalid type", makeTIFF(binary.LittleEndian, 0, 0x0112, 2, 6), 1, false},
+		{"tiff truncated", makeTIFF(binary.LittleEndian, 0, 0x0112, 3, 6)[:20], 0, true},
+		{"jpeg", makeJPEG(exif(makeTIFF(binary.BigEndian, 0, 0x0112, 3, 5))), 5, false},
+		{"jpeg little endian", makeJPEG(exif(makeTIFF(binary.LittleEndian, 0, 0x0112, 3, 7))), 7, false},
+		{"jpeg no exif", makeJPEG(nil), 1, false},
+		{"jpeg xmp", makeJPEG([]byte("http://ns.adobe.com/xap/1.0/\x00")), 1, false},
+		{"jpeg invalid exif", makeJPEG(exif([]byte("XX\x00*\x00\x00\x00\x08"))), 0, true},
+		{"jpeg truncated", makeJPEG(nil)[:10], 0, true},
+		{"jpeg invalid marker", []byte("\xff\xd8\x00\x00"), 0, true},
+		{"png", []byte("\x89PNG\r\n\x1a\n"), 0, true},
+		{"empty", []byte{}, 0, true},
+	}
+
+	for _, d := range testData {
+		got, err := ReadOrientation(bytes.NewReader(d.data))
+		if (err != nil) != d.err {
+			t.Errorf("test [%s]: unexpected error %v", d.desc, err)
+			continue
+		}
+		if got != d.want {
+			t.Errorf("test [%s]: expected %d got %d", d.desc, d.want, got)
+		}
+	}
+}
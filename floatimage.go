@@ -0,0 +1,77 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+)
+
+// FloatImage is an in-memory image with float32 samples that are not limited to the range [0, 1].
+// It can hold high dynamic range colors (e.g. decoded from an OpenEXR or Radiance HDR file)
+// and signed values such as the details of a Laplacian pyramid. The alpha is not premultiplied.
+//
+// The filters read the samples of a FloatImage src without clamping, but the images passed between
+// the filters of a GIFT are clamped to [0, 1], so the filters that make use of the values above 1
+// (e.g. the tone mapping filters) must be the first ones.
+type FloatImage struct {
+	// Pix holds the image samples in the R, G, B, A order. The pixel at (x, y) starts at
+	// Pix[(y-Rect.Min.Y)*Stride + (x-Rect.Min.X)*4].
+	Pix []float32
+	// Stride is the Pix stride (in samples) between two vertically adjacent pixels.
+	Stride int
+	// Rect is the image bounds.
+	Rect image.Rectangle
+}
+
+// NewFloatImage returns a new FloatImage with the given bounds.
+func NewFloatImage(r image.Rectangle) *FloatImage {
+	w, h := r.Dx(), r.Dy()
+	if w <= 0 || h <= 0 {
+		return &FloatImage{Rect: r}
+	}
+	return &FloatImage{
+		Pix:    make([]float32, 4*w*h),
+		Stride: 4 * w,
+		Rect:   r,
+	}
+}
+
+// ColorModel returns the color model of the image. The colors returned by At are clamped to the NRGBA64 range.
+func (p *FloatImage) ColorModel() color.Model {
+	return color.NRGBA64Model
+}
+
+// Bounds returns the image bounds.
+func (p *FloatImage) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+// At returns the color of the pixel at (x, y) clamped to the NRGBA64 range.
+func (p *FloatImage) At(x, y int) color.Color {
+	r, g, b, a := p.FloatAt(x, y)
+	return color.NRGBA64{f32u16(r * 0xffff), f32u16(g * 0xffff), f32u16(b * 0xffff), f32u16(a * 0xffff)}
+}
+
+// FloatAt returns the samples of the pixel at (x, y). It returns zeros outside of the image bounds.
+func (p *FloatImage) FloatAt(x, y int) (r, g, b, a float32) {
+	if !image.Pt(x, y).In(p.Rect) {
+		return 0, 0, 0, 0
+	}
+	i := p.PixOffset(x, y)
+	s := p.Pix[i : i+4 : i+4]
+	return s[0], s[1], s[2], s[3]
+}
+
+// SetFloat sets the samples of the pixel at (x, y). It does nothing outside of the image bounds.
+func (p *FloatImage) SetFloat(x, y int, r, g, b, a float32) {
+	if !image.Pt(x, y).In(p.Rect) {
+		return
+	}
+	i := p.PixOffset(x, y)
+	s := p.Pix[i : i+4 : i+4]
+	s[0], s[1], s[2], s[3] = r, g, b, a
+}
+
+// PixOffset returns the index of the first sample of the pixel at (x, y) in Pix.
+func (p *FloatImage) PixOffset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*4
+}
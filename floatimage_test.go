@@ -0,0 +1,41 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFloatImage(t *testing.T) {
+	img := NewFloatImage(image.Rect(1, 2, 3, 5))
+	if !img.Bounds().Eq(image.Rect(1, 2, 3, 5)) || img.Stride != 8 || len(img.Pix) != 24 {
+		t.Fatalf("unexpected image: %v %d %d", img.Bounds(), img.Stride, len(img.Pix))
+	}
+
+	img.SetFloat(2, 3, 2, -0.5, 0.25, 1)
+	img.SetFloat(0, 0, 1, 1, 1, 1)
+	if r, g, b, a := img.FloatAt(2, 3); r != 2 || g != -0.5 || b != 0.25 || a != 1 {
+		t.Errorf("FloatAt: got %v %v %v %v", r, g, b, a)
+	}
+	if r, g, b, a := img.FloatAt(0, 0); r != 0 || g != 0 || b != 0 || a != 0 {
+		t.Errorf("FloatAt outside: got %v %v %v %v", r, g, b, a)
+	}
+	if i := img.PixOffset(2, 3); i != 12 {
+		t.Errorf("PixOffset: got %d want 12", i)
+	}
+
+	want := color.NRGBA64{0xffff, 0, 0x4000, 0xffff}
+	if c := img.At(2, 3); c != want {
+		t.Errorf("At: got %v want %v", c, want)
+	}
+
+	px := newPixelGetter(img).getPixel(2, 3)
+	if px != (pixel{2, -0.5, 0.25, 1}) {
+		t.Errorf("getPixel: got %v", px)
+	}
+
+	empty := NewFloatImage(image.Rect(0, 0, 0, 5))
+	if !empty.Bounds().Empty() || len(empty.Pix) != 0 {
+		t.Errorf("empty image: %v %d", empty.Bounds(), len(empty.Pix))
+	}
+}
@@ -21,6 +21,7 @@ package gift
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 )
 
@@ -35,6 +36,9 @@ type Filter interface {
 // Options is the parameters passed to image processing filters.
 type Options struct {
 	Parallelization bool
+	LinearLight     bool
+	BorderMode      BorderMode
+	BorderColor     color.Color
 }
 
 var defaultOptions = Options{
@@ -66,6 +70,48 @@ func (g *GIFT) Parallelization() bool {
 	return g.Options.Parallelization
 }
 
+// SetLinearLight enables or disables the linear light processing mode.
+// When enabled, the resampling and smoothing filters (Resize, ResizeToFit, ResizeToFill,
+// GaussianBlur, UnsharpMask, Mean and Convolution with non-negative kernels) convert the colors
+// from sRGB to linear RGB before processing and back to sRGB after processing.
+// It avoids the darkening of fine details and high-contrast edges.
+// Linear light processing is disabled by default.
+func (g *GIFT) SetLinearLight(isEnabled bool) {
+	g.Options.LinearLight = isEnabled
+}
+
+// LinearLight returns the current state of linear light processing option.
+func (g *GIFT) LinearLight() bool {
+	return g.Options.LinearLight
+}
+
+// SetBorderMode sets the way the neighborhood filters (Convolution, GaussianBlur, UnsharpMask, Mean,
+// Median, Minimum, Maximum, Sobel) and the interpolators of the geometric transformations extend
+// the image beyond its bounds. ReflectBorderMode avoids the artifacts at the image edges
+// and WrapBorderMode makes the result of a filter applied to a tileable texture tileable too.
+// The geometric transformations (Rotate, Affine, Perspective, etc.) fill the area beyond the image bounds
+// with their background color when the mode is ClampBorderMode or ConstantBorderMode.
+// The border mode is ClampBorderMode by default.
+func (g *GIFT) SetBorderMode(mode BorderMode) {
+	g.Options.BorderMode = mode
+}
+
+// BorderMode returns the current border mode.
+func (g *GIFT) BorderMode() BorderMode {
+	return g.Options.BorderMode
+}
+
+// SetBorderColor sets the color of the area beyond the image bounds used by the neighborhood filters
+// in ConstantBorderMode. If the color is nil (the default), the area is transparent.
+func (g *GIFT) SetBorderColor(c color.Color) {
+	g.Options.BorderColor = c
+}
+
+// BorderColor returns the current border color.
+func (g *GIFT) BorderColor() color.Color {
+	return g.Options.BorderColor
+}
+
 // Add appends the given filters to the list of filters.
 func (g *GIFT) Add(filters ...Filter) {
 	g.Filters = append(g.Filters, filters...)
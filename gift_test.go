@@ -1,6 +1,7 @@
 package gift
 
 import (
+	"bytes"
 	"image"
 	"image/color"
 	"image/draw"
@@ -37,6 +38,25 @@ func TestGIFT(t *testing.T) {
 	if g.Parallelization() {
 		t.Error("unexpected parallelization property")
 	}
+	if g.LinearLight() != defaultOptions.LinearLight {
+		t.Error("unexpected linear light property")
+	}
+	g.SetLinearLight(true)
+	if !g.LinearLight() {
+		t.Error("unexpected linear light property")
+	}
+	g.SetLinearLight(false)
+	if g.LinearLight() {
+		t.Error("unexpected linear light property")
+	}
+	if g.BorderMode() != ClampBorderMode || g.BorderColor() != nil {
+		t.Error("unexpected border properties")
+	}
+	g.SetBorderMode(WrapBorderMode)
+	g.SetBorderColor(color.White)
+	if g.BorderMode() != WrapBorderMode || g.BorderColor() != color.White {
+		t.Error("unexpected border properties")
+	}
 
 	g = New(
 		&testFilter{1},
@@ -518,6 +538,99 @@ func TestDraw(t *testing.T) {
 	}
 }
 
+func TestLinearLight(t *testing.T) {
+	// Black and white stripes averaged in sRGB and in linear light.
+	src := image.NewGray(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x += 2 {
+			src.Pix[y*src.Stride+x] = 0xff
+		}
+	}
+
+	testData := []struct {
+		desc   string
+		filter Filter
+		white  float32
+	}{
+		{"resize", Resize(16, 16, BoxResampling), 0.5},
+		{"resize to fit", ResizeToFit(16, 16, LinearResampling), 0.5},
+		{"resize to fill", ResizeToFill(16, 8, LinearResampling, CenterAnchor), 0.5},
+		{"gaussian blur", GaussianBlur(3), 0.5},
+		{"mean", Mean(5, false), 0.6},
+	}
+
+	for _, d := range testData {
+		for _, linear := range []bool{false, true} {
+			g := New(d.filter)
+			g.SetLinearLight(linear)
+			dst := image.NewGray(g.Bounds(src.Bounds()))
+			g.Draw(dst, src)
+			want := int(d.white*0xff + 0.5)
+			if linear {
+				want = int(linearToSRGB(d.white)*0xff + 0.5)
+			}
+			b := dst.Bounds()
+			x, y := b.Min.X+b.Dx()/2, b.Min.Y+b.Dy()/2
+			got := int(dst.GrayAt(x, y).Y)
+			if got < want-1 || got > want+1 {
+				t.Errorf("test [%s, linear=%v] failed: expected %#x got %#x", d.desc, linear, want, got)
+			}
+		}
+	}
+
+	// Filters that don't average pixels are not affected.
+	g := New(Convolution([]float32{0, 0, 0, -1, 1, 1, 0, 0, 0}, false, false, false, 0))
+	g.SetLinearLight(true)
+	dst := image.NewGray(g.Bounds(src.Bounds()))
+	g.Draw(dst, src)
+	g.SetLinearLight(false)
+	dst2 := image.NewGray(g.Bounds(src.Bounds()))
+	g.Draw(dst2, src)
+	if !bytes.Equal(dst.Pix, dst2.Pix) {
+		t.Errorf("test [convolution] failed: linear light changed the result")
+	}
+}
+
+func TestTransparentEdges(t *testing.T) {
+	// An opaque white square on a transparent black background.
+	// Filters that average pixels must not darken the edges of the square.
+	src := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for y := 4; y < 12; y++ {
+		for x := 4; x < 12; x++ {
+			src.SetNRGBA(x, y, color.NRGBA{0xff, 0xff, 0xff, 0xff})
+		}
+	}
+
+	filters := map[string]Filter{
+		"resize linear":  Resize(11, 11, LinearResampling),
+		"resize cubic":   Resize(7, 0, CubicResampling),
+		"resize lanczos": Resize(24, 24, LanczosResampling),
+		"resize box":     Resize(5, 5, BoxResampling),
+		"gaussian blur":  GaussianBlur(1.5),
+		"mean":           Mean(5, false),
+		"mean disk":      Mean(5, true),
+		"convolution":    Convolution([]float32{1, 2, 1, 2, 4, 2, 1, 2, 1}, true, true, false, 0),
+		"rotate linear":  Rotate(30, color.Transparent, LinearInterpolation),
+		"rotate cubic":   Rotate(30, color.Transparent, CubicInterpolation),
+		"rotate nearest": Rotate(30, color.Transparent, NearestNeighborInterpolation),
+		"unsharp mask":   UnsharpMask(1, 0.5, 0),
+	}
+
+	for name, f := range filters {
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		for i := 0; i < len(dst.Pix); i += 4 {
+			if dst.Pix[i+3] == 0 {
+				continue
+			}
+			if dst.Pix[i] < 0xfe || dst.Pix[i+1] < 0xfe || dst.Pix[i+2] < 0xfe {
+				t.Errorf("test [%s] failed: dark fringe pixel %#v", name, dst.Pix[i:i+4])
+				break
+			}
+		}
+	}
+}
+
 func loadImage(t *testing.T, filename string) image.Image {
 	f, err := os.Open(filename)
 	if err != nil {
@@ -1 +1,3 @@
 module github.com/disintegration/gift
+
+go 1.16
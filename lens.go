@@ -0,0 +1,219 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// LensModel is a Brown–Conrady lens distortion model.
+// The coordinates are normalized: the origin is at the image center and the distance
+// from the center to the image corners is 1.
+type LensModel struct {
+	// K1, K2 and K3 are the radial distortion coefficients.
+	// Negative values correspond to barrel distortion, positive values to pincushion distortion.
+	K1, K2, K3 float32
+	// P1 and P2 are the tangential distortion coefficients.
+	P1, P2 float32
+}
+
+// Distort maps a point of an ideal (undistorted) image to the corresponding point of the distorted image.
+func (m LensModel) Distort(x, y float32) (float32, float32) {
+	r2 := x*x + y*y
+	radial := 1 + r2*(m.K1+r2*(m.K2+r2*m.K3))
+	xd := x*radial + 2*m.P1*x*y + m.P2*(r2+2*x*x)
+	yd := y*radial + m.P1*(r2+2*y*y) + 2*m.P2*x*y
+	return xd, yd
+}
+
+// Undistort maps a point of the distorted image to the corresponding point of the ideal image.
+// The model has no closed-form inverse, so the point is found iteratively.
+func (m LensModel) Undistort(xd, yd float32) (float32, float32) {
+	x, y := xd, yd
+	for i := 0; i < 20; i++ {
+		r2 := x*x + y*y
+		radial := 1 + r2*(m.K1+r2*(m.K2+r2*m.K3))
+		if radial <= 0 {
+			break
+		}
+		dx := 2*m.P1*x*y + m.P2*(r2+2*x*x)
+		dy := m.P1*(r2+2*y*y) + 2*m.P2*x*y
+		x = (xd - dx) / radial
+		y = (yd - dy) / radial
+	}
+	return x, y
+}
+
+// LensFit defines how a lens distortion filter deals with the areas of the result not covered by the src image.
+type LensFit int
+
+// Lens fit modes.
+const (
+	// NoLensFit keeps the image size and scale, the uncovered areas are filled with the background color.
+	NoLensFit LensFit = iota
+	// CropLensFit keeps the scale and crops the result to the largest centered rectangle
+	// with the image aspect ratio that is completely covered by the src image.
+	CropLensFit
+	// ScaleLensFit keeps the image size and scales the result so it is completely covered by the src image
+	// and shows as much of it as possible.
+	ScaleLensFit
+)
+
+type lensDistortionFilter struct {
+	model         LensModel
+	fit           LensFit
+	inverse       bool
+	bgcolor       color.Color
+	interpolation Interpolation
+}
+
+// mapPoint maps a normalized point of the result to the normalized point of the src image.
+func (p *lensDistortionFilter) mapPoint(x, y float32) (float32, float32) {
+	if p.inverse {
+		return p.model.Undistort(x, y)
+	}
+	return p.model.Distort(x, y)
+}
+
+// covered reports whether the normalized rectangle with the half-size (a, b) zoomed by z
+// is completely covered by the src image with the half-size (sa, sb).
+func (p *lensDistortionFilter) covered(a, b, z, sa, sb float32) bool {
+	const steps = 32
+	for i := 0; i <= steps; i++ {
+		t := 2*float32(i)/steps - 1
+		for _, pt := range [4][2]float32{{t * a, -b}, {t * a, b}, {-a, t * b}, {a, t * b}} {
+			x, y := p.mapPoint(pt[0]*z, pt[1]*z)
+			if absf32(x) > sa || absf32(y) > sb {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// geometry calculates the size of the result and the zoom factor applied to its normalized coordinates.
+func (p *lensDistortionFilter) geometry(srcb image.Rectangle) (w, h int, zoom float32) {
+	w, h = srcb.Dx(), srcb.Dy()
+	if w <= 0 || h <= 0 {
+		return 0, 0, 1
+	}
+	norm := sqrtf32(float32(w*w+h*h)) / 2
+	a, b := float32(w)/2/norm, float32(h)/2/norm
+
+	// search finds the largest value in (0, max] for which the rectangle is covered.
+	search := func(max float32, covered func(v float32) bool) float32 {
+		if covered(max) {
+			return max
+		}
+		lo, hi := float32(0), max
+		for i := 0; i < 30; i++ {
+			mid := (lo + hi) / 2
+			if covered(mid) {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		return lo
+	}
+
+	switch p.fit {
+	case CropLensFit:
+		t := search(1, func(t float32) bool {
+			return p.covered(a*t, b*t, 1, a, b)
+		})
+		w = maxint(int(float32(w)*t+0.5), 1)
+		h = maxint(int(float32(h)*t+0.5), 1)
+		// Make the centering exact.
+		if (srcb.Dx()-w)%2 != 0 {
+			w--
+		}
+		if (srcb.Dy()-h)%2 != 0 {
+			h--
+		}
+		if w <= 0 || h <= 0 {
+			return 0, 0, 1
+		}
+	case ScaleLensFit:
+		max := float32(1)
+		for max < 16 && p.covered(a, b, max*2, a, b) {
+			max *= 2
+		}
+		zoom = search(max*2, func(z float32) bool {
+			return p.covered(a, b, z, a, b)
+		})
+		if zoom <= 0 {
+			zoom = 1
+		}
+		return w, h, zoom
+	}
+	return w, h, 1
+}
+
+func (p *lensDistortionFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h, _ := p.geometry(srcBounds)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *lensDistortionFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	w, h, zoom := p.geometry(srcb)
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	norm := sqrtf32(float32(srcb.Dx()*srcb.Dx()+srcb.Dy()*srcb.Dy())) / 2
+	srccx := float32(srcb.Min.X) + float32(srcb.Dx())/2
+	srccy := float32(srcb.Min.Y) + float32(srcb.Dy())/2
+	dstcx, dstcy := float32(w)/2, float32(h)/2
+
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		nx := (x + 0.5 - dstcx) / norm * zoom
+		ny := (y + 0.5 - dstcy) / norm * zoom
+		sx, sy := p.mapPoint(nx, ny)
+		return srccx + sx*norm - 0.5, srccy + sy*norm - 0.5, true
+	}, interpolator{interpolation: p.interpolation}, pixelFromColor(p.bgcolor), options)
+}
+
+// LensDistortion creates a filter that corrects the lens distortion described by the model,
+// e.g. the barrel distortion of a wide-angle camera.
+// The fit parameter specifies how the areas not covered by the src image after the correction are handled.
+// The backgroundColor parameter specifies the color of these areas.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.LensDistortion(gift.LensModel{K1: -0.12, K2: 0.02}, gift.CropLensFit, color.Black, gift.CubicInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func LensDistortion(model LensModel, fit LensFit, backgroundColor color.Color, interpolation Interpolation) Filter {
+	return &lensDistortionFilter{
+		model:         model,
+		fit:           fit,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
+
+// SimulateLensDistortion creates a filter that applies the lens distortion described by the model to an image.
+// It is the inverse of LensDistortion with the same model.
+// The fit parameter specifies how the areas not covered by the src image after the distortion are handled.
+// The backgroundColor parameter specifies the color of these areas.
+// The interpolation parameter specifies the interpolation method.
+func SimulateLensDistortion(model LensModel, fit LensFit, backgroundColor color.Color, interpolation Interpolation) Filter {
+	return &lensDistortionFilter{
+		model:         model,
+		fit:           fit,
+		inverse:       true,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
@@ -0,0 +1,134 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestLensModel(t *testing.T) {
+	models := []LensModel{
+		{},
+		{K1: -0.2},
+		{K1: 0.15, K2: 0.05},
+		{K1: -0.1, K2: 0.01, K3: -0.001, P1: 0.01, P2: -0.02},
+	}
+	points := [][2]float32{{0, 0}, {0.3, -0.2}, {-0.6, 0.5}, {0.8, 0.6}}
+
+	for _, m := range models {
+		for _, pt := range points {
+			xd, yd := m.Distort(pt[0], pt[1])
+			x, y := m.Undistort(xd, yd)
+			if absf32(x-pt[0]) > 1e-4 || absf32(y-pt[1]) > 1e-4 {
+				t.Errorf("model %v, point %v: got (%v, %v)", m, pt, x, y)
+			}
+		}
+	}
+
+	// Barrel distortion moves the points towards the center.
+	x, y := LensModel{K1: -0.2}.Distort(0.6, 0.8)
+	if absf32(x-0.48) > 1e-6 || absf32(y-0.64) > 1e-6 {
+		t.Errorf("barrel distortion: got (%v, %v)", x, y)
+	}
+}
+
+func TestLensDistortion(t *testing.T) {
+	src := image.NewGray(image.Rect(-3, -2, 3, 2))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i + 1)
+	}
+
+	// A zero model doesn't change the image.
+	for _, fit := range []LensFit{NoLensFit, CropLensFit, ScaleLensFit} {
+		for _, f := range []Filter{
+			LensDistortion(LensModel{}, fit, color.White, NearestNeighborInterpolation),
+			SimulateLensDistortion(LensModel{}, fit, color.White, LinearInterpolation),
+		} {
+			dst := image.NewGray(f.Bounds(src.Bounds()))
+			f.Draw(dst, src, nil)
+			if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 6, 4), dst.Pix, src.Pix) {
+				t.Errorf("zero model, fit %d: %#v, %#v", fit, dst.Bounds(), dst.Pix)
+			}
+		}
+	}
+
+	// Empty images.
+	f := LensDistortion(LensModel{K1: 0.1}, CropLensFit, color.White, NearestNeighborInterpolation)
+	if b := f.Bounds(image.Rect(0, 0, 0, 0)); !b.Empty() {
+		t.Errorf("unexpected bounds %v", b)
+	}
+	f.Draw(image.NewGray(image.Rect(0, 0, 0, 0)), image.NewGray(image.Rect(0, 0, 0, 0)), nil)
+}
+
+func TestLensDistortionFit(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 60, 40))
+	for i := range src.Pix {
+		src.Pix[i] = 0x80
+	}
+
+	countBackground := func(f Filter) (image.Rectangle, int) {
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		n := 0
+		for _, c := range dst.Pix {
+			if c != 0x80 {
+				n++
+			}
+		}
+		return dst.Bounds(), n
+	}
+
+	for _, m := range []LensModel{{K1: 0.3}, {K1: -0.3}, {K1: 0.1, P1: 0.02, P2: 0.03}} {
+		for _, simulate := range []bool{false, true} {
+			newFilter := LensDistortion
+			if simulate {
+				newFilter = SimulateLensDistortion
+			}
+
+			b, n := countBackground(newFilter(m, NoLensFit, color.Black, NearestNeighborInterpolation))
+			if !b.Eq(src.Bounds()) {
+				t.Errorf("model %v, simulate %v, no fit: unexpected bounds %v", m, simulate, b)
+			}
+			corrBarrel := !simulate && m.K1 < 0 || simulate && m.K1 > 0
+			if !corrBarrel && n == 0 {
+				t.Errorf("model %v, simulate %v, no fit: expected uncovered areas", m, simulate)
+			}
+
+			b, n = countBackground(newFilter(m, CropLensFit, color.Black, NearestNeighborInterpolation))
+			if n != 0 || b.Dx() > 60 || b.Dy() > 40 || b.Dx() < 30 || b.Dy() < 20 || (60-b.Dx())%2 != 0 || (40-b.Dy())%2 != 0 {
+				t.Errorf("model %v, simulate %v, crop fit: bounds %v, %d uncovered pixels", m, simulate, b, n)
+			}
+
+			b, n = countBackground(newFilter(m, ScaleLensFit, color.Black, NearestNeighborInterpolation))
+			if n != 0 || !b.Eq(src.Bounds()) {
+				t.Errorf("model %v, simulate %v, scale fit: bounds %v, %d uncovered pixels", m, simulate, b, n)
+			}
+		}
+	}
+}
+
+func TestLensDistortionRoundTrip(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			src.Pix[y*src.Stride+x] = uint8(x*2 + y)
+		}
+	}
+
+	m := LensModel{K1: -0.15, K2: 0.02, P1: 0.005}
+	g := New(
+		SimulateLensDistortion(m, NoLensFit, color.Black, CubicInterpolation),
+		LensDistortion(m, NoLensFit, color.Black, CubicInterpolation),
+	)
+	dst := image.NewGray(g.Bounds(src.Bounds()))
+	g.Draw(dst, src)
+
+	for y := 12; y < 36; y++ {
+		for x := 16; x < 48; x++ {
+			i := y*src.Stride + x
+			if absf32(float32(dst.Pix[i])-float32(src.Pix[i])) > 2 {
+				t.Fatalf("pixel (%d, %d): expected %#x got %#x", x, y, src.Pix[i], dst.Pix[i])
+			}
+		}
+	}
+}
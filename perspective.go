@@ -0,0 +1,207 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// PerspectiveMatrix is a 3x3 projective transformation matrix (homography) {a, b, c, d, e, f, g, h, i}
+// that maps the point (x, y) to the point ((a*x + b*y + c) / w, (d*x + e*y + f) / w), where w = g*x + h*y + i.
+// The coordinates are in the image coordinate system: the y axis points down
+// and the pixel (x, y) covers the square from (x, y) to (x+1, y+1).
+type PerspectiveMatrix [9]float32
+
+// NewPerspectiveMatrix calculates the perspective matrix that maps each of the four from points
+// to the corresponding to point. If no such matrix exists (e.g. three of the points are on the same line),
+// the second return value is false.
+func NewPerspectiveMatrix(from, to [4][2]float32) (PerspectiveMatrix, bool) {
+	// Solve the linear system for the first 8 matrix elements, the last one is 1.
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y := float64(from[i][0]), float64(from[i][1])
+		u, v := float64(to[i][0]), float64(to[i][1])
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -x * u, -y * u, u}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -x * v, -y * v, v}
+	}
+
+	// Gaussian elimination with partial pivoting.
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-10 {
+			return PerspectiveMatrix{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			k := a[row][col] / a[col][col]
+			for j := col; j < 9; j++ {
+				a[row][j] -= k * a[col][j]
+			}
+		}
+	}
+
+	var m PerspectiveMatrix
+	for i := 0; i < 8; i++ {
+		m[i] = float32(a[i][8] / a[i][i])
+	}
+	m[8] = 1
+	return m, true
+}
+
+// Invert returns the matrix of the inverse transformation.
+// If the matrix is not invertible, the second return value is false.
+func (m PerspectiveMatrix) Invert() (PerspectiveMatrix, bool) {
+	var d [9]float64
+	for i := range m {
+		d[i] = float64(m[i])
+	}
+	c0 := d[4]*d[8] - d[5]*d[7]
+	c1 := d[5]*d[6] - d[3]*d[8]
+	c2 := d[3]*d[7] - d[4]*d[6]
+	det := d[0]*c0 + d[1]*c1 + d[2]*c2
+	if math.Abs(det) < 1e-12 {
+		return PerspectiveMatrix{}, false
+	}
+	q := 1 / det
+	return PerspectiveMatrix{
+		float32(c0 * q),
+		float32((d[2]*d[7] - d[1]*d[8]) * q),
+		float32((d[1]*d[5] - d[2]*d[4]) * q),
+		float32(c1 * q),
+		float32((d[0]*d[8] - d[2]*d[6]) * q),
+		float32((d[2]*d[3] - d[0]*d[5]) * q),
+		float32(c2 * q),
+		float32((d[1]*d[6] - d[0]*d[7]) * q),
+		float32((d[0]*d[4] - d[1]*d[3]) * q),
+	}, true
+}
+
+// Transform returns the transformed point. If the point is mapped to infinity or beyond it
+// (the point is on or behind the horizon line), the third return value is false.
+func (m PerspectiveMatrix) Transform(x, y float32) (float32, float32, bool) {
+	w := m[6]*x + m[7]*y + m[8]
+	if w <= 1e-6 {
+		return 0, 0, false
+	}
+	return (m[0]*x + m[1]*y + m[2]) / w, (m[3]*x + m[4]*y + m[5]) / w, true
+}
+
+type perspectiveFilter struct {
+	matrix        PerspectiveMatrix
+	ok            bool
+	mode          BoundsMode
+	fixed         bool
+	width, height int
+	bgcolor       color.Color
+	interpolation Interpolation
+}
+
+// geometry calculates the size of the transformed image and the position of its top-left corner.
+func (p *perspectiveFilter) geometry(srcb image.Rectangle) (w, h int, ox, oy float32) {
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 {
+		return 0, 0, 0, 0
+	}
+	if p.fixed {
+		if p.width <= 0 || p.height <= 0 {
+			return 0, 0, 0, 0
+		}
+		return p.width, p.height, 0, 0
+	}
+	mode := p.mode
+	if !p.ok {
+		mode = KeepBoundsMode
+	}
+	// An unbounded transformed image can't be fitted.
+	for _, pt := range [4]image.Point{srcb.Min, {srcb.Max.X, srcb.Min.Y}, srcb.Max, {srcb.Min.X, srcb.Max.Y}} {
+		if _, _, ok := p.matrix.Transform(float32(pt.X), float32(pt.Y)); !ok {
+			mode = KeepBoundsMode
+		}
+	}
+	return warpGeometry(srcb, mode, func(x, y float32) (float32, float32) {
+		x, y, _ = p.matrix.Transform(x, y)
+		return x, y
+	})
+}
+
+func (p *perspectiveFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h, _, _ := p.geometry(srcBounds)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *perspectiveFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	w, h, ox, oy := p.geometry(src.Bounds())
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	inv, ok := p.matrix.Invert()
+	ok = ok && p.ok
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		if !ok {
+			return 0, 0, false
+		}
+		xf, yf, ok := inv.Transform(ox+x+0.5, oy+y+0.5)
+		return xf - 0.5, yf - 0.5, ok
+	}, interpolator{interpolation: p.interpolation}, pixelFromColor(p.bgcolor), options)
+}
+
+// Perspective creates a filter that applies a perspective transformation to an image.
+// The transformation maps each of the four from points in the src image to the corresponding to point.
+// Use NewPerspectiveMatrix with the same points to transform other coordinates (e.g. annotations)
+// and its Invert method to map them back.
+// The mode parameter specifies whether the original bounds are kept or the bounds are fitted to the transformed image.
+// If the transformed image is unbounded, the original bounds are kept.
+// The backgroundColor parameter specifies the color of the areas not covered by the transformed image.
+// The interpolation parameter specifies the interpolation method.
+func Perspective(from, to [4][2]float32, mode BoundsMode, backgroundColor color.Color, interpolation Interpolation) Filter {
+	m, ok := NewPerspectiveMatrix(from, to)
+	return &perspectiveFilter{
+		matrix:        m,
+		ok:            ok,
+		mode:          mode,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
+
+// Rectify creates a filter that extracts a quadrilateral region of an image (e.g. a photographed document page)
+// and maps it to a width x height rectangle, correcting the perspective distortion.
+// The corners parameter is the top-left, top-right, bottom-right and bottom-left corners of the region in the src image.
+//
+// Example:
+//
+//	// Extract an A4 page at 100 dpi.
+//	corners := [4][2]float32{{120, 80}, {940, 130}, {990, 1260}, {70, 1210}}
+//	g := gift.New(
+//		gift.Rectify(corners, 827, 1169, gift.CubicInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Rectify(corners [4][2]float32, width, height int, interpolation Interpolation) Filter {
+	w, h := float32(width), float32(height)
+	m, ok := NewPerspectiveMatrix(corners, [4][2]float32{{0, 0}, {w, 0}, {w, h}, {0, h}})
+	return &perspectiveFilter{
+		matrix:        m,
+		ok:            ok,
+		fixed:         true,
+		width:         width,
+		height:        height,
+		bgcolor:       color.Transparent,
+		interpolation: interpolation,
+	}
+}
@@ -0,0 +1,242 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPerspectiveMatrix(t *testing.T) {
+	near := func(x0, y0, x1, y1 float32) bool {
+		return absf32(x0-x1) < 1e-3 && absf32(y0-y1) < 1e-3
+	}
+
+	testData := []struct {
+		desc     string
+		from, to [4][2]float32
+	}{
+		{
+			"identity",
+			[4][2]float32{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			[4][2]float32{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+		},
+		{
+			"affine",
+			[4][2]float32{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+			[4][2]float32{{5, 5}, {25, 5}, {25, 15}, {5, 15}},
+		},
+		{
+			"keystone",
+			[4][2]float32{{12, 8}, {94, 13}, {99, 126}, {7, 121}},
+			[4][2]float32{{0, 0}, {80, 0}, {80, 120}, {0, 120}},
+		},
+	}
+
+	for _, d := range testData {
+		m, ok := NewPerspectiveMatrix(d.from, d.to)
+		if !ok {
+			t.Errorf("test [%s]: expected a valid matrix", d.desc)
+			continue
+		}
+		inv, ok := m.Invert()
+		if !ok {
+			t.Errorf("test [%s]: expected an invertible matrix", d.desc)
+			continue
+		}
+		for i := range d.from {
+			x, y, ok := m.Transform(d.from[i][0], d.from[i][1])
+			if !ok || !near(x, y, d.to[i][0], d.to[i][1]) {
+				t.Errorf("test [%s]: point %d: expected %v got (%v, %v, %v)", d.desc, i, d.to[i], x, y, ok)
+			}
+			x, y, ok = inv.Transform(d.to[i][0], d.to[i][1])
+			if !ok || !near(x, y, d.from[i][0], d.from[i][1]) {
+				t.Errorf("test [%s]: inverse point %d: expected %v got (%v, %v, %v)", d.desc, i, d.from[i], x, y, ok)
+			}
+		}
+	}
+
+	// Three points on the same line.
+	_, ok := NewPerspectiveMatrix(
+		[4][2]float32{{0, 0}, {1, 1}, {2, 2}, {0, 1}},
+		[4][2]float32{{0, 0}, {10, 0}, {10, 10}, {0, 10}},
+	)
+	if ok {
+		t.Errorf("expected an invalid matrix")
+	}
+}
+
+func TestPerspective(t *testing.T) {
+	square := [4][2]float32{{0, 0}, {3, 0}, {3, 2}, {0, 2}}
+
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"perspective identity",
+			Perspective(square, square, KeepBoundsMode, color.White, CubicInterpolation),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+		},
+		{
+			"perspective translation keep",
+			Perspective(square, [4][2]float32{{1, 0}, {4, 0}, {4, 2}, {1, 2}}, KeepBoundsMode, color.White, NearestNeighborInterpolation),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0xff, 0x01, 0x02,
+				0xff, 0x04, 0x05,
+			},
+		},
+		{
+			"perspective scaling fit",
+			Perspective(square, [4][2]float32{{0, 0}, {6, 0}, {6, 2}, {0, 2}}, FitBoundsMode, color.White, NearestNeighborInterpolation),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 6, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x01, 0x01, 0x02, 0x02, 0x03, 0x03,
+				0x04, 0x04, 0x05, 0x05, 0x06, 0x06,
+			},
+		},
+		{
+			"perspective invalid",
+			Perspective([4][2]float32{{0, 0}, {1, 1}, {2, 2}, {0, 1}}, square, FitBoundsMode, color.White, NearestNeighborInterpolation),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"rectify center",
+			Rectify([4][2]float32{{1, 1}, {3, 1}, {3, 3}, {1, 3}}, 2, 2, LinearInterpolation),
+			image.Rect(-1, -1, 3, 3),
+			image.Rect(0, 0, 2, 2),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0x09, 0x0a, 0x0b, 0x0c,
+				0x0d, 0x0e, 0x0f, 0x10,
+			},
+			[]uint8{
+				0x0b, 0x0c,
+				0x0f, 0x10,
+			},
+		},
+		{
+			"rectify scale",
+			Rectify([4][2]float32{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, 4, 4, NearestNeighborInterpolation),
+			image.Rect(0, 0, 2, 2),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x01, 0x02,
+				0x03, 0x04,
+			},
+			[]uint8{
+				0x01, 0x01, 0x02, 0x02,
+				0x01, 0x01, 0x02, 0x02,
+				0x03, 0x03, 0x04, 0x04,
+				0x03, 0x03, 0x04, 0x04,
+			},
+		},
+		{
+			"rectify 0x0",
+			Rectify(square, 0, 2, NearestNeighborInterpolation),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{},
+		},
+		{
+			"perspective 0x0",
+			Perspective(square, square, FitBoundsMode, color.White, NearestNeighborInterpolation),
+			image.Rect(0, 0, 0, 0),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestPerspectiveRectify(t *testing.T) {
+	// A checkerboard with 8x8 squares.
+	src := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if (x/8+y/8)%2 == 0 {
+				src.Pix[y*src.Stride+x] = 0xff
+			}
+		}
+	}
+
+	square := [4][2]float32{{0, 0}, {64, 0}, {64, 64}, {0, 64}}
+	quad := [4][2]float32{{6, 3}, {58, 0}, {64, 61}, {0, 64}}
+
+	f := Perspective(square, quad, KeepBoundsMode, color.Black, LinearInterpolation)
+	distorted := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(distorted, src, nil)
+
+	f = Rectify(quad, 64, 64, LinearInterpolation)
+	dst := image.NewGray(f.Bounds(distorted.Bounds()))
+	f.Draw(dst, distorted, nil)
+
+	if !dst.Bounds().Eq(src.Bounds()) {
+		t.Fatalf("unexpected bounds %v", dst.Bounds())
+	}
+	// Compare the pixels away from the edges of the squares that are blurred by the interpolation.
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if x%8 < 2 || x%8 > 5 || y%8 < 2 || y%8 > 5 {
+				continue
+			}
+			i := y*src.Stride + x
+			if absf32(float32(dst.Pix[i])-float32(src.Pix[i])) > 0x40 {
+				t.Fatalf("rectified image differs from the original at (%d, %d): %#x, %#x", x, y, dst.Pix[i], src.Pix[i])
+			}
+		}
+	}
+
+	// A transformation that moves a part of the image beyond the horizon keeps the original bounds.
+	f = Perspective(square, [4][2]float32{{24, 0}, {40, 0}, {64, 10}, {0, 10}}, FitBoundsMode, color.Black, LinearInterpolation)
+	if b := f.Bounds(image.Rect(0, 0, 128, 128)); !b.Eq(image.Rect(0, 0, 128, 128)) {
+		t.Errorf("unexpected bounds %v", b)
+	}
+}
@@ -0,0 +1,364 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+)
+
+type pixelArtAlgorithm int
+
+const (
+	pixelArtScale2x pixelArtAlgorithm = iota
+	pixelArtScale3x
+	pixelArtXBR
+)
+
+type pixelArtFilter struct {
+	algorithm pixelArtAlgorithm
+	scale     int
+	blend     bool
+}
+
+func (p *pixelArtFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	if p.scale < 1 {
+		return image.Rect(0, 0, 0, 0)
+	}
+	dstBounds = image.Rect(0, 0, srcBounds.Dx()*p.scale, srcBounds.Dy()*p.scale)
+	return
+}
+
+// pixelArtBuffer is a copy of the source image pixels with clamped access beyond the edges.
+type pixelArtBuffer struct {
+	w, h int
+	pix  []pixel
+}
+
+func (b *pixelArtBuffer) at(x, y int) pixel {
+	x = minint(maxint(x, 0), b.w-1)
+	y = minint(maxint(y, 0), b.h-1)
+	return b.pix[y*b.w+x]
+}
+
+func (p *pixelArtFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+	w, h := srcb.Dx(), srcb.Dy()
+
+	if w <= 0 || h <= 0 || p.scale < 1 {
+		return
+	}
+
+	if p.algorithm == pixelArtScale2x && p.scale == 4 {
+		tmp := createTempImage(image.Rect(0, 0, w*2, h*2))
+		Scale2x().Draw(tmp, src, options)
+		Scale2x().Draw(dst, tmp, options)
+		return
+	}
+
+	buf := &pixelArtBuffer{w: w, h: h, pix: make([]pixel, w*h)}
+	pixGetter := newPixelGetter(src)
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < w; x++ {
+				buf.pix[y*w+x] = pixGetter.getPixel(srcb.Min.X+x, srcb.Min.Y+y)
+			}
+		}
+	})
+
+	var weights [][]float32
+	if p.algorithm == pixelArtXBR {
+		weights = xbrWeights(p.scale)
+	}
+
+	n := p.scale
+	pixSetter := newPixelSetter(dst)
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		block := make([]pixel, n*n)
+		for y := start; y < stop; y++ {
+			for x := 0; x < w; x++ {
+				switch p.algorithm {
+				case pixelArtScale2x:
+					scale2x(buf, x, y, block)
+				case pixelArtScale3x:
+					scale3x(buf, x, y, block)
+				default:
+					xbr(buf, x, y, n, weights, p.blend, block)
+				}
+				for j := 0; j < n; j++ {
+					for i := 0; i < n; i++ {
+						pixSetter.setPixel(dstb.Min.X+x*n+i, dstb.Min.Y+y*n+j, block[j*n+i])
+					}
+				}
+			}
+		}
+	})
+}
+
+// scale2x implements the Scale2x (EPX) algorithm.
+//
+//	  A
+//	C P B
+//	  D
+func scale2x(buf *pixelArtBuffer, x, y int, block []pixel) {
+	a := buf.at(x, y-1)
+	c := buf.at(x-1, y)
+	p := buf.at(x, y)
+	b := buf.at(x+1, y)
+	d := buf.at(x, y+1)
+
+	block[0], block[1], block[2], block[3] = p, p, p, p
+	if c == a && c != d && a != b {
+		block[0] = a
+	}
+	if a == b && a != c && b != d {
+		block[1] = b
+	}
+	if d == c && d != b && c != a {
+		block[2] = c
+	}
+	if b == d && b != a && d != c {
+		block[3] = d
+	}
+}
+
+// scale3x implements the Scale3x algorithm.
+//
+//	A B C
+//	D E F
+//	G H I
+func scale3x(buf *pixelArtBuffer, x, y int, block []pixel) {
+	a := buf.at(x-1, y-1)
+	b := buf.at(x, y-1)
+	c := buf.at(x+1, y-1)
+	d := buf.at(x-1, y)
+	e := buf.at(x, y)
+	f := buf.at(x+1, y)
+	g := buf.at(x-1, y+1)
+	h := buf.at(x, y+1)
+	i := buf.at(x+1, y+1)
+
+	for k := range block[:9] {
+		block[k] = e
+	}
+	if b == d || b == f || h == d || h == f {
+		// Fast path for the most common case.
+		if b != h && d != f {
+			if d == b {
+				block[0] = d
+			}
+			if (d == b && e != c) || (b == f && e != a) {
+				block[1] = b
+			}
+			if b == f {
+				block[2] = f
+			}
+			if (d == b && e != g) || (d == h && e != a) {
+				block[3] = d
+			}
+			if (b == f && e != i) || (h == f && e != c) {
+				block[5] = f
+			}
+			if d == h {
+				block[6] = d
+			}
+			if (d == h && e != i) || (h == f && e != g) {
+				block[7] = h
+			}
+			if h == f {
+				block[8] = f
+			}
+		}
+	}
+}
+
+// xbrWeights calculates the blending weights of the subpixels of an n x n output block
+// for the bottom-right corner. The detected edge is a 45 degree line going through the middle
+// of the right and the bottom sides of the block, the weight is the fraction of the subpixel area
+// beyond the line.
+func xbrWeights(n int) [][]float32 {
+	const samples = 16
+	// The sample coordinates are measured in halves of the sample size so the comparisons are exact.
+	// The samples lying on the line count as half, so the subpixels split by the line in halves get 0.5.
+	half := samples * n
+	line := 3 * samples * n
+	weights := make([][]float32, n)
+	for j := 0; j < n; j++ {
+		weights[j] = make([]float32, n)
+		for i := 0; i < n; i++ {
+			cnt := 0
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					u := 2*(i*samples+sx) + 1
+					v := 2*(j*samples+sy) + 1
+					if u < half || v < half {
+						continue
+					}
+					if d := u + v - line; d > 0 {
+						cnt += 2
+					} else if d == 0 {
+						cnt++
+					}
+				}
+			}
+			weights[j][i] = float32(cnt) / (2 * samples * samples)
+		}
+	}
+	return weights
+}
+
+// xbrDist returns the weighted YUV distance between two pixels used by the xBR algorithm.
+func xbrDist(p1, p2 pixel) float32 {
+	r, g, b := p1.r-p2.r, p1.g-p2.g, p1.b-p2.b
+	y := 0.299*r + 0.587*g + 0.114*b
+	u := -0.169*r - 0.331*g + 0.5*b
+	v := 0.5*r - 0.419*g - 0.081*b
+	return 48*absf32(y) + 7*absf32(u) + 6*absf32(v) + 48*absf32(p1.a-p2.a)
+}
+
+// xbr implements the edge detection rule of the xBR algorithm (level 1).
+// The neighborhood of the E pixel is rotated so that each corner is processed as the bottom-right one.
+//
+//	   A1 B1 C1
+//	A0  A  B  C C4
+//	D0  D  E  F F4
+//	G0  G  H  I I4
+//	   G5 H5 I5
+func xbr(buf *pixelArtBuffer, x, y, n int, weights [][]float32, blend bool, block []pixel) {
+	e := buf.at(x, y)
+	for k := range block[:n*n] {
+		block[k] = e
+	}
+
+	for rot := 0; rot < 4; rot++ {
+		// at returns the neighbor at the (dx, dy) offset in the rotated frame.
+		at := func(dx, dy int) pixel {
+			for r := 0; r < rot; r++ {
+				dx, dy = -dy, dx
+			}
+			return buf.at(x+dx, y+dy)
+		}
+
+		f := at(1, 0)
+		h := at(0, 1)
+		if e == f || e == h {
+			continue
+		}
+		i := at(1, 1)
+		b := at(0, -1)
+		c := at(1, -1)
+		d := at(-1, 0)
+		g := at(-1, 1)
+		f4 := at(2, 0)
+		i4 := at(2, 1)
+		h5 := at(0, 2)
+		i5 := at(1, 2)
+
+		wd1 := xbrDist(e, c) + xbrDist(e, g) + xbrDist(i, f4) + xbrDist(i, h5) + 4*xbrDist(h, f)
+		wd2 := xbrDist(h, d) + xbrDist(h, i5) + xbrDist(f, i4) + xbrDist(f, b) + 4*xbrDist(e, i)
+		if wd1 >= wd2 {
+			continue
+		}
+
+		px := h
+		if xbrDist(e, f) <= xbrDist(e, h) {
+			px = f
+		}
+
+		for j := 0; j < n; j++ {
+			for i := 0; i < n; i++ {
+				// Map the subpixel of the rotated frame back to the block.
+				ox, oy := 2*i-(n-1), 2*j-(n-1)
+				for r := 0; r < rot; r++ {
+					ox, oy = -oy, ox
+				}
+				k := (oy+(n-1))/2*n + (ox+(n-1))/2
+
+				w := weights[j][i]
+				if !blend {
+					if w >= 0.5 {
+						block[k] = px
+					}
+					continue
+				}
+				if w > 0 {
+					block[k] = blendPixels(block[k], px, w)
+				}
+			}
+		}
+	}
+}
+
+// blendPixels linearly interpolates between two pixels weighting the colors by alpha.
+func blendPixels(p0, p1 pixel, w float32) pixel {
+	a := p0.a*(1-w) + p1.a*w
+	if a <= 0 {
+		return pixel{0, 0, 0, 0}
+	}
+	return pixel{
+		(p0.r*p0.a*(1-w) + p1.r*p1.a*w) / a,
+		(p0.g*p0.a*(1-w) + p1.g*p1.a*w) / a,
+		(p0.b*p0.a*(1-w) + p1.b*p1.a*w) / a,
+		a,
+	}
+}
+
+// Scale2x creates a filter that enlarges an image 2 times using the Scale2x (EPX) pixel art scaling algorithm.
+// Edges are smoothed without introducing new colors, so the palette and the alpha values of the image are preserved exactly.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.Scale2x(),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Scale2x() Filter {
+	return &pixelArtFilter{
+		algorithm: pixelArtScale2x,
+		scale:     2,
+	}
+}
+
+// Scale3x creates a filter that enlarges an image 3 times using the Scale3x pixel art scaling algorithm.
+// The palette and the alpha values of the image are preserved exactly.
+func Scale3x() Filter {
+	return &pixelArtFilter{
+		algorithm: pixelArtScale3x,
+		scale:     3,
+	}
+}
+
+// Scale4x creates a filter that enlarges an image 4 times by applying the Scale2x algorithm twice.
+// The palette and the alpha values of the image are preserved exactly.
+func Scale4x() Filter {
+	return &pixelArtFilter{
+		algorithm: pixelArtScale2x,
+		scale:     4,
+	}
+}
+
+// XBR creates a filter that enlarges an image using the xBR pixel art scaling algorithm.
+// The scale parameter must be in range [2, 4].
+// If blend is true, the pixels along the detected edges are blended producing smoother results,
+// otherwise only the colors of the original image are used, so the palette is preserved exactly.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.XBR(4, true),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func XBR(scale int, blend bool) Filter {
+	return &pixelArtFilter{
+		algorithm: pixelArtXBR,
+		scale:     minint(maxint(scale, 2), 4),
+		blend:     blend,
+	}
+}
@@ -0,0 +1,221 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestPixelArt(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"scale2x",
+			Scale2x(),
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 6, 6),
+			[]uint8{
+				0xff, 0x00, 0x00,
+				0x00, 0xff, 0x00,
+				0x00, 0x00, 0xff,
+			},
+			[]uint8{
+				0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+				0xff, 0x00, 0xff, 0x00, 0x00, 0x00,
+				0x00, 0xff, 0xff, 0xff, 0x00, 0x00,
+				0x00, 0x00, 0xff, 0xff, 0xff, 0x00,
+				0x00, 0x00, 0x00, 0xff, 0x00, 0xff,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff,
+			},
+		},
+		{
+			"scale3x",
+			Scale3x(),
+			image.Rect(-1, -1, 1, 1),
+			image.Rect(0, 0, 6, 6),
+			[]uint8{
+				0xff, 0x00,
+				0x00, 0xff,
+			},
+			[]uint8{
+				0xff, 0xff, 0xff, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0x00, 0xff, 0x00, 0x00,
+				0xff, 0x00, 0x00, 0xff, 0xff, 0x00,
+				0x00, 0xff, 0xff, 0x00, 0x00, 0xff,
+				0x00, 0x00, 0xff, 0x00, 0xff, 0xff,
+				0x00, 0x00, 0x00, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"scale2x flat",
+			Scale2x(),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 4, 2),
+			[]uint8{0x80, 0x80},
+			[]uint8{
+				0x80, 0x80, 0x80, 0x80,
+				0x80, 0x80, 0x80, 0x80,
+			},
+		},
+		{
+			"scale4x 0x0",
+			Scale4x(),
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+		{
+			"xbr 2x no blend",
+			XBR(2, false),
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 6, 6),
+			[]uint8{
+				0x00, 0x00, 0x00,
+				0x00, 0x00, 0xff,
+				0x00, 0xff, 0xff,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0xff,
+				0x00, 0x00, 0x00, 0xff, 0xff, 0xff,
+				0x00, 0x00, 0x00, 0xff, 0xff, 0xff,
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"xbr 0x0",
+			XBR(2, true),
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestPixelArtPalette(t *testing.T) {
+	palette := []color.NRGBA{
+		{0x00, 0x00, 0x00, 0x00},
+		{0xff, 0x00, 0x00, 0xff},
+		{0x00, 0x80, 0xff, 0xff},
+		{0xff, 0xff, 0x00, 0x80},
+	}
+	src := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			src.SetNRGBA(x, y, palette[(x*x+y+x*y/3)%len(palette)])
+		}
+	}
+	inPalette := func(c color.NRGBA) bool {
+		for _, p := range palette {
+			if c == p {
+				return true
+			}
+		}
+		return false
+	}
+
+	filters := map[string]Filter{
+		"scale2x":         Scale2x(),
+		"scale3x":         Scale3x(),
+		"scale4x":         Scale4x(),
+		"xbr 2 no blend":  XBR(2, false),
+		"xbr 3 no blend":  XBR(3, false),
+		"xbr 4 no blend":  XBR(4, false),
+		"xbr 10 no blend": XBR(10, false),
+	}
+
+	for name, f := range filters {
+		dst := image.NewNRGBA(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		scale := dst.Bounds().Dx() / 8
+		if dst.Bounds().Dx() != dst.Bounds().Dy() || scale < 2 || scale > 4 || dst.Bounds().Dx()%8 != 0 {
+			t.Errorf("test [%s]: bad bounds %v", name, dst.Bounds())
+			continue
+		}
+		for y := 0; y < dst.Bounds().Dy(); y++ {
+			for x := 0; x < dst.Bounds().Dx(); x++ {
+				if c := dst.NRGBAAt(x, y); !inPalette(c) {
+					t.Errorf("test [%s]: color %v at (%d, %d) is not in the palette", name, c, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestXBR(t *testing.T) {
+	// A diagonal edge between black and white.
+	src := image.NewGray(image.Rect(0, 0, 6, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 6; x++ {
+			if x > y {
+				src.Pix[y*src.Stride+x] = 0xff
+			}
+		}
+	}
+
+	for _, scale := range []int{2, 3, 4} {
+		f := XBR(scale, true)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !dst.Bounds().Eq(image.Rect(0, 0, 6*scale, 6*scale)) {
+			t.Errorf("XBR(%d): bad bounds %v", scale, dst.Bounds())
+			continue
+		}
+
+		// The algorithm doesn't depend on the edge orientation.
+		tsrc := image.NewGray(Transpose().Bounds(src.Bounds()))
+		Transpose().Draw(tsrc, src, nil)
+		tdst := image.NewGray(f.Bounds(tsrc.Bounds()))
+		f.Draw(tdst, tsrc, nil)
+
+		blended := false
+		for y := 0; y < 6*scale; y++ {
+			for x := 0; x < 6*scale; x++ {
+				v := dst.Pix[y*dst.Stride+x]
+				if tv := tdst.Pix[x*tdst.Stride+y]; v != tv {
+					t.Errorf("XBR(%d): transposed result differs at (%d, %d): %#x, %#x", scale, x, y, v, tv)
+				}
+				if v != 0x00 && v != 0xff {
+					blended = true
+				}
+			}
+		}
+		if !blended {
+			t.Errorf("XBR(%d): expected blended pixels along the edge", scale)
+		}
+	}
+
+	// Flat images must not change.
+	flat := image.NewGray(image.Rect(0, 0, 3, 3))
+	for i := range flat.Pix {
+		flat.Pix[i] = 0x40
+	}
+	f := XBR(3, true)
+	dst := image.NewGray(f.Bounds(flat.Bounds()))
+	f.Draw(dst, flat, nil)
+	for _, v := range dst.Pix {
+		if v != 0x40 {
+			t.Errorf("XBR(3): flat image changed: %#v", dst.Pix)
+			break
+		}
+	}
+}
@@ -22,6 +22,7 @@ const (
 	itGray
 	itGray16
 	itPaletted
+	itFloat
 )
 
 type pixelGetter struct {
@@ -37,6 +38,7 @@ type pixelGetter struct {
 	ycbcr    *image.YCbCr
 	paletted *image.Paletted
 	palette  []pixel
+	float    *FloatImage
 }
 
 func newPixelGetter(img image.Image) *pixelGetter {
@@ -98,6 +100,13 @@ func newPixelGetter(img image.Image) *pixelGetter {
 			palette:  convertPalette(img.Palette),
 		}
 
+	case *FloatImage:
+		return &pixelGetter{
+			it:     itFloat,
+			bounds: img.Bounds(),
+			float:  img,
+		}
+
 	default:
 		return &pixelGetter{
 			it:     itGeneric,
@@ -273,6 +282,12 @@ func (p *pixelGetter) getPixel(x, y int) pixel {
 		i := p.paletted.PixOffset(x, y)
 		k := p.paletted.Pix[i]
 		return p.palette[k]
+
+	case itFloat:
+		// The samples are not clamped.
+		i := p.float.PixOffset(x, y)
+		s := p.float.Pix[i : i+4 : i+4]
+		return pixel{s[0], s[1], s[2], s[3]}
 	}
 
 	return pixelFromColor(p.image.At(x, y))
@@ -56,6 +56,11 @@ func TestNewPixelGetter(t *testing.T) {
 	if pg.it != itGeneric || pg.image == nil || !img.Bounds().Eq(pg.bounds) {
 		t.Error("newPixelGetter Generic(Alpha)")
 	}
+	img = NewFloatImage(image.Rect(0, 0, 1, 1))
+	pg = newPixelGetter(img)
+	if pg.it != itFloat || pg.float == nil || !img.Bounds().Eq(pg.bounds) {
+		t.Error("newPixelGetter Float")
+	}
 }
 
 func comparePixels(px1, px2 pixel, dif float64) bool {
@@ -0,0 +1,292 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+)
+
+// PolarMode defines how the distance from the center is mapped to the rows of a polar image.
+type PolarMode int
+
+// Polar modes.
+const (
+	// LinearPolarMode maps the distance from the center linearly.
+	LinearPolarMode PolarMode = iota
+	// LogPolarMode maps the logarithm of the distance from the center,
+	// so the area near the center gets more rows than the periphery.
+	LogPolarMode
+)
+
+// polarRadius converts the relative position of a row of a polar image (0 at the center, 1 at the radius) to the distance.
+func polarRadius(t, radius float32, mode PolarMode) float32 {
+	if mode == LogPolarMode {
+		return float32(math.Pow(float64(radius)+1, float64(t))) - 1
+	}
+	return t * radius
+}
+
+// polarRow converts the distance from the center to the relative position of a row of a polar image.
+func polarRow(r, radius float32, mode PolarMode) float32 {
+	if mode == LogPolarMode {
+		return float32(math.Log1p(float64(r)) / math.Log1p(float64(radius)))
+	}
+	return r / radius
+}
+
+// polarSize returns the size of a polar or Cartesian image, the default size is used for the values <= 0.
+func polarSize(width, height, defWidth, defHeight int) (int, int) {
+	if width <= 0 {
+		width = maxint(defWidth, 1)
+	}
+	if height <= 0 {
+		height = maxint(defHeight, 1)
+	}
+	return width, height
+}
+
+type toPolarFilter struct {
+	cx, cy, radius float32
+	width, height  int
+	mode           PolarMode
+	bgcolor        color.Color
+	interpolation  Interpolation
+}
+
+func (p *toPolarFilter) size(srcb image.Rectangle) (int, int) {
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 || p.radius <= 0 {
+		return 0, 0
+	}
+	return polarSize(p.width, p.height, int(2*math.Pi*p.radius+0.5), int(p.radius+0.5))
+}
+
+func (p *toPolarFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h := p.size(srcBounds)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *toPolarFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	w, h := p.size(src.Bounds())
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		angle := 2 * math.Pi * float64(x+0.5) / float64(w)
+		r := polarRadius((y+0.5)/float32(h), p.radius, p.mode)
+		sin, cos := math.Sincos(angle)
+		return p.cx + r*float32(cos) - 0.5, p.cy + r*float32(sin) - 0.5, true
+	}, interpolator{interpolation: p.interpolation}, pixelFromColor(p.bgcolor), options)
+}
+
+// ToPolar creates a filter that maps the circle with the center (cx, cy) and the given radius
+// to a width x height rectangle: the columns of the result correspond to the angles starting
+// at the positive x axis and going clockwise, the rows correspond to the distances from the center
+// starting at the center at the top. It unwraps circular objects such as gauges and dials.
+// If width or height is not positive, it defaults to the circumference or the radius respectively.
+// The mode parameter specifies how the distances are mapped to the rows.
+// The backgroundColor parameter specifies the color of the areas outside of the src image.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	// Unwrap the dial with the center at (320, 240) and the radius 200.
+//	g := gift.New(
+//		gift.ToPolar(320, 240, 200, 1256, 200, gift.LinearPolarMode, color.Black, gift.LinearInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ToPolar(cx, cy, radius float32, width, height int, mode PolarMode, backgroundColor color.Color, interpolation Interpolation) Filter {
+	return &toPolarFilter{
+		cx:            cx,
+		cy:            cy,
+		radius:        radius,
+		width:         width,
+		height:        height,
+		mode:          mode,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
+
+type fromPolarFilter struct {
+	cx, cy, radius float32
+	width, height  int
+	mode           PolarMode
+	bgcolor        color.Color
+	interpolation  Interpolation
+}
+
+func (p *fromPolarFilter) size(srcb image.Rectangle) (int, int) {
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 || p.radius <= 0 {
+		return 0, 0
+	}
+	d := int(2*p.radius + 0.5)
+	return polarSize(p.width, p.height, d, d)
+}
+
+func (p *fromPolarFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h := p.size(srcBounds)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *fromPolarFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	w, h := p.size(srcb)
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	srcw, srch := float32(srcb.Dx()), float32(srcb.Dy())
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		dx, dy := x+0.5-p.cx, y+0.5-p.cy
+		r := sqrtf32(dx*dx + dy*dy)
+		if r > p.radius {
+			return 0, 0, false
+		}
+		angle := math.Atan2(float64(dy), float64(dx))
+		if angle < 0 {
+			angle += 2 * math.Pi
+		}
+		// Clamp the coordinates to avoid blending with the background at the seam and at the center.
+		xf := minf32(maxf32(float32(angle/(2*math.Pi))*srcw-0.5, 0), srcw-1)
+		yf := minf32(maxf32(polarRow(r, p.radius, p.mode)*srch-0.5, 0), srch-1)
+		return float32(srcb.Min.X) + xf, float32(srcb.Min.Y) + yf, true
+	}, interpolator{interpolation: p.interpolation}, pixelFromColor(p.bgcolor), options)
+}
+
+// FromPolar creates a filter that is the inverse of ToPolar: it maps a polar image to
+// the circle with the center (cx, cy) and the given radius in a width x height image.
+// If width or height is not positive, it defaults to the circle diameter.
+// The mode parameter specifies how the rows of the polar image are mapped to the distances from the center.
+// The backgroundColor parameter specifies the color of the areas outside of the circle.
+// The interpolation parameter specifies the interpolation method.
+func FromPolar(cx, cy, radius float32, width, height int, mode PolarMode, backgroundColor color.Color, interpolation Interpolation) Filter {
+	return &fromPolarFilter{
+		cx:            cx,
+		cy:            cy,
+		radius:        radius,
+		width:         width,
+		height:        height,
+		mode:          mode,
+		bgcolor:       backgroundColor,
+		interpolation: interpolation,
+	}
+}
+
+type cylinderFilter struct {
+	cx, radius    float32
+	unwrap        bool
+	interpolation Interpolation
+}
+
+// geometry calculates the width of the result and the range of the positions on the cylinder surface
+// covered by the src image, measured in radians from the cylinder axis.
+func (p *cylinderFilter) geometry(srcb image.Rectangle) (w, h int, a0, a1 float32) {
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 || p.radius <= 0 {
+		return 0, 0, 0, 0
+	}
+	x0, x1 := float32(srcb.Min.X)-p.cx, float32(srcb.Max.X)-p.cx
+	var fw float32
+	if p.unwrap {
+		// The src image is a photo of the cylinder, only its visible half can be unwrapped.
+		x0 = minf32(maxf32(x0/p.radius, -1), 1)
+		x1 = minf32(maxf32(x1/p.radius, -1), 1)
+		a0 = float32(math.Asin(float64(x0)))
+		a1 = float32(math.Asin(float64(x1)))
+		fw = (a1 - a0) * p.radius
+	} else {
+		// The src image is the flat surface of the cylinder, only its visible half is wrapped.
+		a0 = minf32(maxf32(x0/p.radius, -math.Pi/2), math.Pi/2)
+		a1 = minf32(maxf32(x1/p.radius, -math.Pi/2), math.Pi/2)
+		fw = (float32(math.Sin(float64(a1))) - float32(math.Sin(float64(a0)))) * p.radius
+	}
+	w = int(fw + 0.5)
+	if w <= 0 {
+		return 0, 0, 0, 0
+	}
+	return w, srcb.Dy(), a0, a1
+}
+
+func (p *cylinderFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h, _, _ := p.geometry(srcBounds)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *cylinderFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	w, h, a0, a1 := p.geometry(srcb)
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	s0 := float32(math.Sin(float64(a0)))
+	s1 := float32(math.Sin(float64(a1)))
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		t := (x + 0.5) / float32(w)
+		var xf float32
+		if p.unwrap {
+			a := a0 + t*(a1-a0)
+			xf = p.cx + p.radius*float32(math.Sin(float64(a)))
+		} else {
+			s := minf32(maxf32(s0+t*(s1-s0), -1), 1)
+			xf = p.cx + p.radius*float32(math.Asin(float64(s)))
+		}
+		return xf - 0.5, float32(srcb.Min.Y) + y, true
+	}, interpolator{interpolation: p.interpolation}, pixel{}, options)
+}
+
+// UnwrapCylinder creates a filter that flattens the surface of a vertical cylinder (e.g. a bottle label)
+// photographed from the front. The cx parameter is the x coordinate of the cylinder axis in the src image
+// and the radius parameter is the cylinder radius in pixels. The visible half of the cylinder is unwrapped
+// so that the horizontal distances in the result are proportional to the distances on the cylinder surface.
+// The interpolation parameter specifies the interpolation method.
+//
+// Example:
+//
+//	// The bottle is 300px wide and centered in the photo.
+//	b := src.Bounds()
+//	g := gift.New(
+//		gift.UnwrapCylinder(float32(b.Min.X+b.Max.X)/2, 150, gift.CubicInterpolation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func UnwrapCylinder(cx, radius float32, interpolation Interpolation) Filter {
+	return &cylinderFilter{
+		cx:            cx,
+		radius:        radius,
+		unwrap:        true,
+		interpolation: interpolation,
+	}
+}
+
+// WrapCylinder creates a filter that is the inverse of UnwrapCylinder: it projects a flat image
+// onto a vertical cylinder as seen from the front. The cx parameter is the x coordinate in the src image
+// that faces the viewer and the radius parameter is the cylinder radius in pixels.
+// The parts of the src image beyond the visible half of the cylinder are cut off.
+// The interpolation parameter specifies the interpolation method.
+func WrapCylinder(cx, radius float32, interpolation Interpolation) Filter {
+	return &cylinderFilter{
+		cx:            cx,
+		radius:        radius,
+		interpolation: interpolation,
+	}
+}
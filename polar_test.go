@@ -0,0 +1,173 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestToPolar(t *testing.T) {
+	// The left half of the image is light, the right half is dark.
+	src := image.NewGray(image.Rect(-10, -10, 10, 10))
+	for y := -10; y < 10; y++ {
+		for x := -10; x < 10; x++ {
+			c := uint8(0xc0)
+			if x >= 0 {
+				c = 0x40
+			}
+			src.SetGray(x, y, color.Gray{c})
+		}
+	}
+
+	f := ToPolar(0, 0, 8, 8, 4, LinearPolarMode, color.White, NearestNeighborInterpolation)
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+
+	row := []uint8{0x40, 0x40, 0xc0, 0xc0, 0xc0, 0xc0, 0x40, 0x40}
+	want := []uint8{}
+	for i := 0; i < 4; i++ {
+		want = append(want, row...)
+	}
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 8, 4), dst.Pix, want) {
+		t.Errorf("unexpected result: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+
+	// The circle goes beyond the image.
+	f = ToPolar(0, 0, 40, 4, 4, LinearPolarMode, color.White, NearestNeighborInterpolation)
+	dst = image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	for x := 0; x < 4; x++ {
+		if dst.Pix[x] == 0xff || dst.Pix[3*dst.Stride+x] != 0xff {
+			t.Errorf("unexpected result: %#v", dst.Pix)
+			break
+		}
+	}
+}
+
+func TestPolarMode(t *testing.T) {
+	// The brightness of a pixel is proportional to its distance from the center.
+	src := image.NewGray(image.Rect(0, 0, 101, 101))
+	for y := 0; y < 101; y++ {
+		for x := 0; x < 101; x++ {
+			dx, dy := float32(x)+0.5-50.5, float32(y)+0.5-50.5
+			src.Pix[y*src.Stride+x] = uint8(sqrtf32(dx*dx+dy*dy)*4 + 0.5)
+		}
+	}
+
+	for _, mode := range []PolarMode{LinearPolarMode, LogPolarMode} {
+		f := ToPolar(50.5, 50.5, 50, 16, 10, mode, color.Black, LinearInterpolation)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		for y := 0; y < 10; y++ {
+			want := polarRadius((float32(y)+0.5)/10, 50, mode) * 4
+			for x := 0; x < 16; x++ {
+				if got := float32(dst.Pix[y*dst.Stride+x]); absf32(got-want) > 4 {
+					t.Fatalf("mode %d, pixel (%d, %d): expected %v got %v", mode, x, y, want, got)
+				}
+			}
+		}
+		if mode == LogPolarMode && dst.Pix[5*dst.Stride] >= 50*4/2 {
+			t.Errorf("log-polar mode: the middle row is too far from the center: %d", dst.Pix[5*dst.Stride])
+		}
+	}
+}
+
+func TestPolarRoundTrip(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			src.Pix[y*src.Stride+x] = uint8(x*3 + y*2)
+		}
+	}
+
+	for _, mode := range []PolarMode{LinearPolarMode, LogPolarMode} {
+		g := New(
+			ToPolar(20, 20, 18, 360, 100, mode, color.Black, CubicInterpolation),
+			FromPolar(20, 20, 18, 40, 40, mode, color.White, CubicInterpolation),
+		)
+		dst := image.NewGray(g.Bounds(src.Bounds()))
+		g.Draw(dst, src)
+		if !dst.Bounds().Eq(src.Bounds()) {
+			t.Fatalf("unexpected bounds %v", dst.Bounds())
+		}
+
+		for y := 0; y < 40; y++ {
+			for x := 0; x < 40; x++ {
+				dx, dy := float32(x)+0.5-20, float32(y)+0.5-20
+				r := sqrtf32(dx*dx + dy*dy)
+				got, want := dst.Pix[y*dst.Stride+x], src.Pix[y*src.Stride+x]
+				if r > 18 && got != 0xff {
+					t.Fatalf("mode %d, pixel (%d, %d): expected background got %#x", mode, x, y, got)
+				}
+				if r < 16 && absf32(float32(got)-float32(want)) > 3 {
+					t.Fatalf("mode %d, pixel (%d, %d): expected %#x got %#x", mode, x, y, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestPolarBounds(t *testing.T) {
+	testData := []struct {
+		desc       string
+		f          Filter
+		srcb, dstb image.Rectangle
+	}{
+		{"to polar", ToPolar(5, 5, 10, 100, 20, LogPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 10, 10), image.Rect(0, 0, 100, 20)},
+		{"to polar default", ToPolar(5, 5, 10, 0, 0, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 10, 10), image.Rect(0, 0, 63, 10)},
+		{"to polar zero radius", ToPolar(5, 5, 0, 100, 20, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 10, 10), image.Rect(0, 0, 0, 0)},
+		{"to polar 0x0", ToPolar(5, 5, 10, 100, 20, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 0, 0), image.Rect(0, 0, 0, 0)},
+		{"from polar", FromPolar(5, 5, 10, 30, 40, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 100, 10), image.Rect(0, 0, 30, 40)},
+		{"from polar default", FromPolar(5, 5, 10, 0, -1, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 100, 10), image.Rect(0, 0, 20, 20)},
+		{"from polar 0x0", FromPolar(5, 5, 10, 30, 40, LinearPolarMode, color.Black, LinearInterpolation), image.Rect(0, 0, 0, 10), image.Rect(0, 0, 0, 0)},
+		{"unwrap cylinder", UnwrapCylinder(5, 5, LinearInterpolation), image.Rect(0, 0, 10, 10), image.Rect(0, 0, 16, 10)},
+		{"unwrap cylinder narrow", UnwrapCylinder(5, 50, LinearInterpolation), image.Rect(0, 0, 10, 10), image.Rect(0, 0, 10, 10)},
+		{"wrap cylinder", WrapCylinder(0, 10, LinearInterpolation), image.Rect(-50, 0, 50, 10), image.Rect(0, 0, 20, 10)},
+		{"wrap cylinder zero radius", WrapCylinder(0, 0, LinearInterpolation), image.Rect(-50, 0, 50, 10), image.Rect(0, 0, 0, 0)},
+	}
+
+	for _, d := range testData {
+		if b := d.f.Bounds(d.srcb); !b.Eq(d.dstb) {
+			t.Errorf("test [%s]: expected %v got %v", d.desc, d.dstb, b)
+		}
+		// Check no panics.
+		src := image.NewGray(d.srcb)
+		d.f.Draw(image.NewGray(d.f.Bounds(d.srcb)), src, nil)
+	}
+}
+
+func TestCylinder(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 100, 3))
+	for y := 0; y < 3; y++ {
+		for x := 0; x < 100; x++ {
+			src.Pix[y*src.Stride+x] = uint8(x * 2)
+		}
+	}
+
+	f := WrapCylinder(50, 40, LinearInterpolation)
+	wrapped := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(wrapped, src, nil)
+	if !wrapped.Bounds().Eq(image.Rect(0, 0, 76, 3)) {
+		t.Fatalf("unexpected wrapped bounds %v", wrapped.Bounds())
+	}
+	// The center is unchanged, the sides are compressed.
+	if c := wrapped.Pix[38]; absf32(float32(c)-100) > 2 {
+		t.Errorf("unexpected center value %#x", c)
+	}
+	if d0, d1 := int(wrapped.Pix[39])-int(wrapped.Pix[38]), int(wrapped.Pix[75])-int(wrapped.Pix[74]); d1 <= d0 {
+		t.Errorf("the sides are not compressed: %d, %d", d0, d1)
+	}
+
+	f = UnwrapCylinder(38, 40, LinearInterpolation)
+	dst := image.NewGray(f.Bounds(wrapped.Bounds()))
+	f.Draw(dst, wrapped, nil)
+	if !dst.Bounds().Eq(image.Rect(0, 0, 100, 3)) {
+		t.Fatalf("unexpected unwrapped bounds %v", dst.Bounds())
+	}
+	for x := 20; x < 80; x++ {
+		if got, want := dst.Pix[dst.Stride+x], src.Pix[src.Stride+x]; absf32(float32(got)-float32(want)) > 4 {
+			t.Fatalf("pixel %d: expected %#x got %#x", x, want, got)
+		}
+	}
+}
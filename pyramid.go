@@ -0,0 +1,184 @@
+package gift
+
+import (
+	"image"
+)
+
+// PyramidType is the type of the low-pass filter used to build an image pyramid.
+type PyramidType int
+
+// Pyramid types.
+const (
+	GaussianPyramid PyramidType = iota
+	BoxPyramid
+)
+
+// PyramidOptions are the image pyramid parameters.
+type PyramidOptions struct {
+	// Type is the low-pass filter applied before downsampling each level.
+	Type PyramidType
+	// Levels is the maximum number of levels including the original image. If it's 0, the number of levels is unlimited.
+	Levels int
+	// MinSize is the minimum width and height of the levels. The pyramid ends before a level smaller than MinSize.
+	// Values less than 1 are treated as 1, so the pyramid continues down to a 1x1 level.
+	MinSize int
+	// PowerOfTwo enables padding the original image to power-of-two dimensions with transparent pixels
+	// at the right and bottom edges, so every level is exactly half the size of the previous one.
+	PowerOfTwo bool
+}
+
+var defaultPyramidOptions = PyramidOptions{}
+
+// nextPowerOfTwo returns the smallest power of two that is greater than or equal to n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
+
+// pyramidLevel resamples img to the given size.
+func pyramidLevel(img *image.NRGBA64, w, h int, resampling Resampling, options *Options) *image.NRGBA64 {
+	dst := image.NewNRGBA64(image.Rect(0, 0, w, h))
+	Resize(w, h, resampling).Draw(dst, img, options)
+	return dst
+}
+
+// Pyramid builds an image pyramid (for example, a set of mipmaps) from the src image.
+// The first level is a copy of the src image (padded if PowerOfTwo is set), and every next level
+// is half the size of the previous one, rounded down, low-pass filtered according to the pyramid type.
+// If the pyramidOptions parameter is nil, a Gaussian pyramid down to 1x1 is built.
+// The options parameter is passed to the resampling filters, if it's nil, the default options are used.
+//
+// Example:
+//
+//	g := gift.New()
+//	g.SetParallelization(false)
+//	levels := gift.Pyramid(src, &gift.PyramidOptions{
+//		Type:    gift.BoxPyramid,
+//		MinSize: 16,
+//	}, &g.Options)
+//
+func Pyramid(src image.Image, pyramidOptions *PyramidOptions, options *Options) []*image.NRGBA64 {
+	if pyramidOptions == nil {
+		pyramidOptions = &defaultPyramidOptions
+	}
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	w, h := srcb.Dx(), srcb.Dy()
+	if w <= 0 || h <= 0 {
+		return nil
+	}
+
+	if pyramidOptions.PowerOfTwo {
+		w, h = nextPowerOfTwo(w), nextPowerOfTwo(h)
+	}
+	base := image.NewNRGBA64(image.Rect(0, 0, w, h))
+	copyimage(base, src, options)
+
+	resampling := GaussianResampling
+	if pyramidOptions.Type == BoxPyramid {
+		resampling = BoxResampling
+	}
+	minSize := maxint(pyramidOptions.MinSize, 1)
+
+	levels := []*image.NRGBA64{base}
+	for pyramidOptions.Levels <= 0 || len(levels) < pyramidOptions.Levels {
+		if w == 1 && h == 1 {
+			break
+		}
+		w, h = maxint(w/2, 1), maxint(h/2, 1)
+		if w < minSize || h < minSize {
+			break
+		}
+		levels = append(levels, pyramidLevel(levels[len(levels)-1], w, h, resampling, options))
+	}
+	return levels
+}
+
+// laplacianDiff calculates the signed difference between two images of the same size.
+// If lowpass is nil, img is copied as is.
+func laplacianDiff(img, lowpass *image.NRGBA64, options *Options) *FloatImage {
+	b := img.Bounds()
+	dst := NewFloatImage(b)
+	pixGetter := newPixelGetter(img)
+	var lowGetter *pixelGetter
+	if lowpass != nil {
+		lowGetter = newPixelGetter(lowpass)
+	}
+	parallelize(options.Parallelization, b.Min.Y, b.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				p0 := pixGetter.getPixel(x, y)
+				var p1 pixel
+				if lowGetter != nil {
+					p1 = lowGetter.getPixel(x, y)
+				}
+				dst.SetFloat(x, y, p0.r-p1.r, p0.g-p1.g, p0.b-p1.b, p0.a-p1.a)
+			}
+		}
+	})
+	return dst
+}
+
+// LaplacianPyramid builds a Laplacian pyramid from the src image. Each level except the last one
+// holds the signed details lost between the corresponding level of the Pyramid and the upsampled next level.
+// The last level is the smallest level of the Pyramid.
+// Use ReconstructLaplacianPyramid with the same options to get the original image back.
+func LaplacianPyramid(src image.Image, pyramidOptions *PyramidOptions, options *Options) []*FloatImage {
+	if options == nil {
+		options = &defaultOptions
+	}
+	levels := Pyramid(src, pyramidOptions, options)
+	if len(levels) == 0 {
+		return nil
+	}
+
+	details := make([]*FloatImage, len(levels))
+	for i := 0; i < len(levels)-1; i++ {
+		b := levels[i].Bounds()
+		up := pyramidLevel(levels[i+1], b.Dx(), b.Dy(), LinearResampling, options)
+		details[i] = laplacianDiff(levels[i], up, options)
+	}
+	details[len(levels)-1] = laplacianDiff(levels[len(levels)-1], nil, options)
+	return details
+}
+
+// ReconstructLaplacianPyramid reconstructs the image from the levels created by LaplacianPyramid.
+// The result has the size of the first level. If the options parameter is nil, the default options are used.
+func ReconstructLaplacianPyramid(levels []*FloatImage, options *Options) *image.NRGBA64 {
+	if len(levels) == 0 {
+		return nil
+	}
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	last := levels[len(levels)-1]
+	img := image.NewNRGBA64(image.Rect(0, 0, last.Bounds().Dx(), last.Bounds().Dy()))
+	copyimage(img, last, options)
+	for i := len(levels) - 2; i >= 0; i-- {
+		b := levels[i].Bounds()
+		up := pyramidLevel(img, b.Dx(), b.Dy(), LinearResampling, options)
+
+		diff := levels[i]
+		upGetter := newPixelGetter(up)
+		dst := image.NewNRGBA64(image.Rect(0, 0, b.Dx(), b.Dy()))
+		pixSetter := newPixelSetter(dst)
+		parallelize(options.Parallelization, 0, b.Dy(), func(start, stop int) {
+			for y := start; y < stop; y++ {
+				for x := 0; x < b.Dx(); x++ {
+					r, g, bl, a := diff.FloatAt(b.Min.X+x, b.Min.Y+y)
+					p := upGetter.getPixel(x, y)
+					pixSetter.setPixel(x, y, pixel{p.r + r, p.g + g, p.b + bl, p.a + a})
+				}
+			}
+		})
+		img = dst
+	}
+	return img
+}
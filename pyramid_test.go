@@ -0,0 +1,205 @@
+package gift
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPyramid(t *testing.T) {
+	testData := []struct {
+		desc    string
+		srcb    image.Rectangle
+		options *PyramidOptions
+		sizes   []image.Point
+	}{
+		{
+			"default",
+			image.Rect(-1, -1, 9, 5),
+			nil,
+			[]image.Point{{10, 6}, {5, 3}, {2, 1}, {1, 1}},
+		},
+		{
+			"min size",
+			image.Rect(-1, -1, 9, 5),
+			&PyramidOptions{MinSize: 3},
+			[]image.Point{{10, 6}, {5, 3}},
+		},
+		{
+			"levels",
+			image.Rect(-1, -1, 9, 5),
+			&PyramidOptions{Type: BoxPyramid, Levels: 2},
+			[]image.Point{{10, 6}, {5, 3}},
+		},
+		{
+			"power of two",
+			image.Rect(-1, -1, 9, 5),
+			&PyramidOptions{PowerOfTwo: true},
+			[]image.Point{{16, 8}, {8, 4}, {4, 2}, {2, 1}, {1, 1}},
+		},
+		{
+			"1x1",
+			image.Rect(0, 0, 1, 1),
+			nil,
+			[]image.Point{{1, 1}},
+		},
+		{
+			"0x0",
+			image.Rect(0, 0, 0, 0),
+			nil,
+			nil,
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewNRGBA(d.srcb)
+		levels := Pyramid(src, d.options, nil)
+		if len(levels) != len(d.sizes) {
+			t.Errorf("test [%s]: expected %d levels got %d", d.desc, len(d.sizes), len(levels))
+			continue
+		}
+		for i, l := range levels {
+			if !l.Bounds().Eq(image.Rectangle{Max: d.sizes[i]}) {
+				t.Errorf("test [%s]: level %d: expected size %v got %v", d.desc, i, d.sizes[i], l.Bounds())
+			}
+		}
+	}
+}
+
+func TestPyramidPixels(t *testing.T) {
+	src := image.NewGray(image.Rect(-1, -1, 3, 1))
+	src.Pix = []uint8{
+		0x10, 0x20, 0x80, 0x80,
+		0x30, 0x40, 0x80, 0x80,
+	}
+
+	levels := Pyramid(src, &PyramidOptions{Type: BoxPyramid}, nil)
+	want := [][]uint8{
+		{0x10, 0x20, 0x80, 0x80, 0x30, 0x40, 0x80, 0x80},
+		{0x28, 0x80},
+		{0x54},
+	}
+	if len(levels) != len(want) {
+		t.Fatalf("expected %d levels got %d", len(want), len(levels))
+	}
+	for i, l := range levels {
+		dst := image.NewGray(l.Bounds())
+		copyimage(dst, l, nil)
+		if string(dst.Pix) != string(want[i]) {
+			t.Errorf("box pyramid level %d: expected %#v got %#v", i, want[i], dst.Pix)
+		}
+	}
+
+	// Padding is transparent.
+	levels = Pyramid(src, &PyramidOptions{PowerOfTwo: true, Levels: 1}, nil)
+	if b := levels[0].Bounds(); !b.Eq(image.Rect(0, 0, 4, 2)) {
+		t.Errorf("unexpected padded bounds %v", b)
+	}
+	levels = Pyramid(image.NewGray(image.Rect(0, 0, 3, 3)), &PyramidOptions{PowerOfTwo: true, Levels: 1}, nil)
+	if c := levels[0].NRGBA64At(3, 3); c.A != 0 {
+		t.Errorf("expected transparent padding got %v", c)
+	}
+	if c := levels[0].NRGBA64At(2, 2); c.A != 0xffff {
+		t.Errorf("expected opaque image got %v", c)
+	}
+
+	// The options are passed to the resampling filters.
+	bw := image.NewGray(image.Rect(0, 0, 2, 1))
+	bw.Pix = []uint8{0x00, 0xff}
+	for _, d := range []struct {
+		options *Options
+		want    uint8
+	}{
+		{nil, 0x80},
+		{&Options{Parallelization: false}, 0x80},
+		{&Options{LinearLight: true}, 0xbc},
+	} {
+		levels := Pyramid(bw, &PyramidOptions{Type: BoxPyramid}, d.options)
+		if c := levels[1].NRGBA64At(0, 0); uint8(c.R>>8) != d.want {
+			t.Errorf("options %+v: expected %#x got %#x", d.options, d.want, c.R>>8)
+		}
+	}
+
+	// Flat images stay flat.
+	flat := image.NewGray(image.Rect(0, 0, 13, 7))
+	for i := range flat.Pix {
+		flat.Pix[i] = 0x60
+	}
+	for _, l := range Pyramid(flat, nil, nil) {
+		b := l.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if c := l.NRGBA64At(x, y); c.R>>8 != 0x60 || c.A != 0xffff {
+					t.Errorf("gaussian pyramid level %v: unexpected color %v at (%d, %d)", b, c, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestLaplacianPyramid(t *testing.T) {
+	src := image.NewNRGBA64(image.Rect(-3, -2, 20, 13))
+	seed := uint32(1)
+	for i := range src.Pix {
+		seed = seed*1664525 + 1013904223
+		src.Pix[i] = uint8(seed >> 24)
+	}
+
+	absdiff := func(v0, v1 uint16) int {
+		if v0 > v1 {
+			return int(v0 - v1)
+		}
+		return int(v1 - v0)
+	}
+
+	for _, d := range []struct {
+		pyramidOptions *PyramidOptions
+		options        *Options
+	}{
+		{nil, nil},
+		{&PyramidOptions{Type: BoxPyramid}, nil},
+		{&PyramidOptions{MinSize: 4}, nil},
+		{&PyramidOptions{PowerOfTwo: true}, nil},
+		{nil, &Options{LinearLight: true}},
+	} {
+		levels := LaplacianPyramid(src, d.pyramidOptions, d.options)
+		if len(levels) < 2 {
+			t.Errorf("options %+v: expected multiple levels got %d", d.pyramidOptions, len(levels))
+			continue
+		}
+
+		// The details are signed.
+		negative := false
+		for _, v := range levels[0].Pix {
+			if v < 0 {
+				negative = true
+				break
+			}
+		}
+		if !negative {
+			t.Errorf("options %+v: expected negative details", d.pyramidOptions)
+		}
+
+		// The original image is reconstructed within the 16-bit precision.
+		want := Pyramid(src, d.pyramidOptions, d.options)[0]
+		img := ReconstructLaplacianPyramid(levels, d.options)
+		if !img.Bounds().Eq(want.Bounds()) {
+			t.Errorf("options %+v: expected bounds %v got %v", d.pyramidOptions, want.Bounds(), img.Bounds())
+			continue
+		}
+		for i := 0; i < len(img.Pix); i += 2 {
+			v0 := uint16(want.Pix[i])<<8 | uint16(want.Pix[i+1])
+			v1 := uint16(img.Pix[i])<<8 | uint16(img.Pix[i+1])
+			if absdiff(v0, v1) > 1 {
+				t.Errorf("options %+v: reconstructed sample %d: expected %#x got %#x", d.pyramidOptions, i/2, v0, v1)
+				break
+			}
+		}
+	}
+
+	if levels := LaplacianPyramid(image.NewNRGBA(image.Rect(0, 0, 0, 0)), nil, nil); levels != nil {
+		t.Errorf("expected no levels")
+	}
+	if img := ReconstructLaplacianPyramid(nil, nil); img != nil {
+		t.Errorf("expected nil image")
+	}
+}
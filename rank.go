@@ -54,7 +54,7 @@ func (p *rankFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 		disk = genDisk(ksize)
 	}
 
-	pixGetter := newPixelGetter(src)
+	pixGetter := newBorderPixelGetter(src, options.BorderMode, borderPixel(options.BorderColor))
 	pixSetter := newPixelSetter(dst)
 
 	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
@@ -75,18 +75,7 @@ func (p *rankFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 			pxbuf = pxbuf[:0]
 			for i := srcb.Min.X - kradius; i <= srcb.Min.X+kradius; i++ {
 				for j := y - kradius; j <= y+kradius; j++ {
-					kx, ky := i, j
-					if kx < srcb.Min.X {
-						kx = srcb.Min.X
-					} else if kx > srcb.Max.X-1 {
-						kx = srcb.Max.X - 1
-					}
-					if ky < srcb.Min.Y {
-						ky = srcb.Min.Y
-					} else if ky > srcb.Max.Y-1 {
-						ky = srcb.Max.Y - 1
-					}
-					pxbuf = append(pxbuf, pixGetter.getPixel(kx, ky))
+					pxbuf = append(pxbuf, pixGetter.getPixel(i, j))
 				}
 			}
 
@@ -168,17 +157,8 @@ func (p *rankFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 					copy(pxbuf[0:], pxbuf[ksize:])
 					pxbuf = pxbuf[0 : ksize*(ksize-1)]
 					kx := x + 1 + kradius
-					if kx > srcb.Max.X-1 {
-						kx = srcb.Max.X - 1
-					}
 					for j := y - kradius; j <= y+kradius; j++ {
-						ky := j
-						if ky < srcb.Min.Y {
-							ky = srcb.Min.Y
-						} else if ky > srcb.Max.Y-1 {
-							ky = srcb.Max.Y - 1
-						}
-						pxbuf = append(pxbuf, pixGetter.getPixel(kx, ky))
+						pxbuf = append(pxbuf, pixGetter.getPixel(kx, j))
 					}
 				}
 			}
@@ -2,6 +2,7 @@ package gift
 
 import (
 	"image"
+	"image/color"
 	"image/draw"
 	"math"
 )
@@ -65,6 +66,67 @@ var CubicResampling Resampling
 // LanczosResampling is a Lanczos resampling filter (3 lobes).
 var LanczosResampling Resampling
 
+// Lanczos2Resampling is a Lanczos resampling filter (2 lobes).
+var Lanczos2Resampling Resampling
+
+// Lanczos4Resampling is a Lanczos resampling filter (4 lobes).
+var Lanczos4Resampling Resampling
+
+// MitchellResampling is a Mitchell-Netravali cubic resampling filter (B = 1/3, C = 1/3).
+var MitchellResampling Resampling
+
+// BSplineResampling is a cubic B-spline resampling filter (B = 1, C = 0). It produces smooth results without ringing.
+var BSplineResampling Resampling
+
+// HermiteResampling is a Hermite resampling filter (cubic with B = 0, C = 0).
+var HermiteResampling Resampling
+
+// HannResampling is a Hann windowed sinc resampling filter (3 lobes).
+var HannResampling Resampling
+
+// HammingResampling is a Hamming windowed sinc resampling filter (3 lobes).
+var HammingResampling Resampling
+
+// BlackmanResampling is a Blackman windowed sinc resampling filter (3 lobes).
+var BlackmanResampling Resampling
+
+// GaussianResampling is a Gaussian resampling filter (sigma = 0.5).
+var GaussianResampling Resampling
+
+// NewResampling creates a custom resampling filter.
+// The name parameter is returned by the String method of the resampling filter.
+// The support parameter is the radius of the kernel, the kernel function must return 0 outside of (-support, support).
+// If the support is 0 or the kernel function is nil, the nearest neighbor resampling is used.
+// The kernel function is evaluated at distances measured in source pixels (or destination pixels when downsampling).
+// The weights are normalized so there's no need for the kernel to be normalized.
+//
+// Example:
+//
+//	// Triangle (bilinear) resampling filter.
+//	triangle := gift.NewResampling("triangle", 1, func(x float32) float32 {
+//		if x < 0 {
+//			x = -x
+//		}
+//		if x < 1 {
+//			return 1 - x
+//		}
+//		return 0
+//	})
+//	g := gift.New(
+//		gift.Resize(300, 0, triangle),
+//	)
+//
+func NewResampling(name string, support float32, kernel func(float32) float32) Resampling {
+	if support < 0 || kernel == nil {
+		support = 0
+	}
+	return resamp{
+		name:    name,
+		support: support,
+		kernel:  kernel,
+	}
+}
+
 type resampWeight struct {
 	index  int
 	weight float32
@@ -151,7 +213,13 @@ func resizeHorizontal(dst draw.Image, src image.Image, w int, resampling Resampl
 		dstBuf := make([]pixel, w)
 		for srcy := start; srcy < stop; srcy++ {
 			pixGetter.getPixelRow(srcy, &srcBuf)
+			if options.LinearLight {
+				pixelsToLinear(srcBuf)
+			}
 			resizeLine(dstBuf, srcBuf, weights)
+			if options.LinearLight {
+				pixelsToSRGB(dstBuf)
+			}
 			pixSetter.setPixelRow(dstb.Min.Y+srcy-srcb.Min.Y, dstBuf)
 		}
 	})
@@ -171,7 +239,13 @@ func resizeVertical(dst draw.Image, src image.Image, h int, resampling Resamplin
 		dstBuf := make([]pixel, h)
 		for srcx := start; srcx < stop; srcx++ {
 			pixGetter.getPixelColumn(srcx, &srcBuf)
+			if options.LinearLight {
+				pixelsToLinear(srcBuf)
+			}
 			resizeLine(dstBuf, srcBuf, weights)
+			if options.LinearLight {
+				pixelsToSRGB(dstBuf)
+			}
 			pixSetter.setPixelColumn(dstb.Min.X+srcx-srcb.Min.X, dstBuf)
 		}
 	})
@@ -266,7 +340,8 @@ func (p *resizeFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 
 // Resize creates a filter that resizes an image to the specified width and height using the specified resampling.
 // If one of width or height is 0, the image aspect ratio is preserved.
-// Supported resampling parameters: NearestNeighborResampling, BoxResampling, LinearResampling, CubicResampling, LanczosResampling.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
 //
 // Example:
 //
@@ -291,16 +366,15 @@ type resizeToFitFilter struct {
 	resampling Resampling
 }
 
-func (p *resizeToFitFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
-	w, h := p.width, p.height
-	srcw, srch := srcBounds.Dx(), srcBounds.Dy()
-
+// fitSize calculates the dimensions of an image of size srcw x srch resized to fit
+// within w x h while preserving the aspect ratio.
+func fitSize(srcw, srch, w, h int, upscale bool) (int, int) {
 	if w <= 0 || h <= 0 || srcw <= 0 || srch <= 0 {
-		return image.Rect(0, 0, 0, 0)
+		return 0, 0
 	}
 
-	if srcw <= w && srch <= h {
-		return image.Rect(0, 0, srcw, srch)
+	if !upscale && srcw <= w && srch <= h {
+		return srcw, srch
 	}
 
 	wratio := float64(srcw) / float64(w)
@@ -315,7 +389,12 @@ func (p *resizeToFitFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
 		dstw = minint(int(float64(srcw)/hratio+0.5), w)
 	}
 
-	return image.Rect(0, 0, dstw, dsth)
+	return dstw, dsth
+}
+
+func (p *resizeToFitFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
+	w, h := fitSize(srcBounds.Dx(), srcBounds.Dy(), p.width, p.height, false)
+	return image.Rect(0, 0, w, h)
 }
 
 func (p *resizeToFitFilter) Draw(dst draw.Image, src image.Image, options *Options) {
@@ -324,7 +403,9 @@ func (p *resizeToFitFilter) Draw(dst draw.Image, src image.Image, options *Optio
 }
 
 // ResizeToFit creates a filter that resizes an image to fit within the specified dimensions while preserving the aspect ratio.
-// Supported resampling parameters: NearestNeighborResampling, BoxResampling, LinearResampling, CubicResampling, LanczosResampling.
+// Images that already fit within the specified dimensions are never upscaled, use ResizeToFitPadded to get the exact dimensions.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
 func ResizeToFit(width, height int, resampling Resampling) Filter {
 	return &resizeToFitFilter{
 		width:      width,
@@ -333,6 +414,121 @@ func ResizeToFit(width, height int, resampling Resampling) Filter {
 	}
 }
 
+// scaleSize calculates the dimension of size n scaled by the factor f, rounded to the nearest integer.
+func scaleSize(n int, f float64) int {
+	return int(math.Max(1, math.Floor(float64(n)*f+0.5)))
+}
+
+type scaleFilter struct {
+	fx, fy     float32
+	resampling Resampling
+}
+
+func (p *scaleFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
+	srcw, srch := srcBounds.Dx(), srcBounds.Dy()
+
+	if p.fx <= 0 || p.fy <= 0 || srcw <= 0 || srch <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+
+	return image.Rect(0, 0, scaleSize(srcw, float64(p.fx)), scaleSize(srch, float64(p.fy)))
+}
+
+func (p *scaleFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	b := p.Bounds(src.Bounds())
+	Resize(b.Dx(), b.Dy(), p.resampling).Draw(dst, src, options)
+}
+
+// Scale creates a filter that resizes an image by the specified scale factors using the specified resampling.
+// The resulting dimensions are rounded to the nearest integer, but are never less than 1 pixel.
+// For example, Scale(0.5, 0.5, gift.LanczosResampling) halves both dimensions of an image.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
+func Scale(factorX, factorY float32, resampling Resampling) Filter {
+	return &scaleFilter{
+		fx:         factorX,
+		fy:         factorY,
+		resampling: resampling,
+	}
+}
+
+type resizeToMaxPixelsFilter struct {
+	maxPixels  int
+	resampling Resampling
+}
+
+func (p *resizeToMaxPixelsFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
+	srcw, srch := srcBounds.Dx(), srcBounds.Dy()
+
+	if p.maxPixels <= 0 || srcw <= 0 || srch <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+
+	if srcw*srch <= p.maxPixels {
+		return image.Rect(0, 0, srcw, srch)
+	}
+
+	f := math.Sqrt(float64(p.maxPixels) / (float64(srcw) * float64(srch)))
+
+	// Rounding to the nearest integer may exceed the limit, so rounding down
+	// is also considered for each of the dimensions.
+	rw, rh := scaleSize(srcw, f), scaleSize(srch, f)
+	fw := int(math.Floor(float64(srcw) * f))
+	fh := int(math.Floor(float64(srch) * f))
+	// For the extreme aspect ratios the short side is clamped to 1 pixel,
+	// so the long side gets the whole limit.
+	if fw < 1 {
+		fw, fh = 1, minint(p.maxPixels, srch)
+	} else if fh < 1 {
+		fw, fh = minint(p.maxPixels, srcw), 1
+	}
+
+	w, h := fw, fh
+	for _, s := range [][2]int{{rw, rh}, {rw, fh}, {fw, rh}} {
+		if n := s[0] * s[1]; n <= p.maxPixels && n > w*h {
+			w, h = s[0], s[1]
+		}
+	}
+
+	return image.Rect(0, 0, w, h)
+}
+
+func (p *resizeToMaxPixelsFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	if p.maxPixels > 0 && srcb.Dx() > 0 && srcb.Dy() > 0 && srcb.Dx()*srcb.Dy() <= p.maxPixels {
+		copyimage(dst, src, options)
+		return
+	}
+
+	b := p.Bounds(srcb)
+	Resize(b.Dx(), b.Dy(), p.resampling).Draw(dst, src, options)
+}
+
+// ResizeToMaxPixels creates a filter that downscales an image preserving the aspect ratio so that
+// its total number of pixels (width * height) doesn't exceed maxPixels. Smaller images are left unchanged.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
+//
+// Example:
+//
+//	// Limit the src image to 12 megapixels.
+//	g := gift.New(
+//		gift.ResizeToMaxPixels(12000000, gift.LanczosResampling),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ResizeToMaxPixels(maxPixels int, resampling Resampling) Filter {
+	return &resizeToMaxPixelsFilter{
+		maxPixels:  maxPixels,
+		resampling: resampling,
+	}
+}
+
 type resizeToFillFilter struct {
 	width      int
 	height     int
@@ -380,7 +576,8 @@ func (p *resizeToFillFilter) Draw(dst draw.Image, src image.Image, options *Opti
 
 // ResizeToFill creates a filter that resizes an image to the smallest possible size that will cover the specified dimensions,
 // then crops the resized image to the specified dimensions using the specified anchor point.
-// Supported resampling parameters: NearestNeighborResampling, BoxResampling, LinearResampling, CubicResampling, LanczosResampling.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
 func ResizeToFill(width, height int, resampling Resampling, anchor Anchor) Filter {
 	return &resizeToFillFilter{
 		width:      width,
@@ -390,6 +587,167 @@ func ResizeToFill(width, height int, resampling Resampling, anchor Anchor) Filte
 	}
 }
 
+// Background defines how the empty areas around a padded image are filled.
+// Use ColorBackground, BlurBackground or EdgeBackground to create a background.
+type Background interface {
+	fill(dst draw.Image, src, fitted image.Image, pt image.Point, resampling Resampling, options *Options)
+}
+
+type colorBackground struct {
+	color color.Color
+}
+
+func (b *colorBackground) fill(dst draw.Image, src, fitted image.Image, pt image.Point, resampling Resampling, options *Options) {
+	dstb := dst.Bounds()
+	fillb := fitted.Bounds().Sub(fitted.Bounds().Min).Add(pt).Add(dstb.Min)
+	px := borderPixel(b.color)
+	pixSetter := newPixelSetter(dst)
+	parallelize(options.Parallelization, dstb.Min.Y, dstb.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := dstb.Min.X; x < dstb.Max.X; x++ {
+				if !image.Pt(x, y).In(fillb) {
+					pixSetter.setPixel(x, y, px)
+				}
+			}
+		}
+	})
+}
+
+// ColorBackground creates a background that fills the empty areas with the specified color.
+// If the color is nil, the empty areas are transparent.
+func ColorBackground(c color.Color) Background {
+	return &colorBackground{
+		color: c,
+	}
+}
+
+type blurBackground struct {
+	sigma float32
+}
+
+func (b *blurBackground) fill(dst draw.Image, src, fitted image.Image, pt image.Point, resampling Resampling, options *Options) {
+	w, h := dst.Bounds().Dx(), dst.Bounds().Dy()
+	tmp := createTempImage(image.Rect(0, 0, w, h))
+	ResizeToFill(w, h, resampling, CenterAnchor).Draw(tmp, src, options)
+	GaussianBlur(b.sigma).Draw(dst, tmp, options)
+}
+
+// BlurBackground creates a background that fills the empty areas with a blurred copy of the image
+// enlarged to cover the whole output. The sigma parameter is the gaussian blur sigma.
+func BlurBackground(sigma float32) Background {
+	return &blurBackground{
+		sigma: sigma,
+	}
+}
+
+type edgeBackground struct{}
+
+func (b *edgeBackground) fill(dst draw.Image, src, fitted image.Image, pt image.Point, resampling Resampling, options *Options) {
+	dstb := dst.Bounds()
+	fb := fitted.Bounds()
+	fillb := fb.Sub(fb.Min).Add(pt).Add(dstb.Min)
+	pixGetter := newPixelGetter(fitted)
+	pixSetter := newPixelSetter(dst)
+	parallelize(options.Parallelization, dstb.Min.Y, dstb.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			fy := fb.Min.Y + minint(maxint(y-fillb.Min.Y, 0), fb.Dy()-1)
+			for x := dstb.Min.X; x < dstb.Max.X; x++ {
+				if image.Pt(x, y).In(fillb) {
+					continue
+				}
+				fx := fb.Min.X + minint(maxint(x-fillb.Min.X, 0), fb.Dx()-1)
+				pixSetter.setPixel(x, y, pixGetter.getPixel(fx, fy))
+			}
+		}
+	})
+}
+
+// EdgeBackground creates a background that fills the empty areas by extending the edge pixels of the image.
+func EdgeBackground() Background {
+	return &edgeBackground{}
+}
+
+type resizeToFitPaddedFilter struct {
+	width      int
+	height     int
+	resampling Resampling
+	anchor     Anchor
+	background Background
+}
+
+func (p *resizeToFitPaddedFilter) Bounds(srcBounds image.Rectangle) image.Rectangle {
+	w, h := p.width, p.height
+	srcw, srch := srcBounds.Dx(), srcBounds.Dy()
+
+	if w <= 0 || h <= 0 || srcw <= 0 || srch <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+
+	return image.Rect(0, 0, w, h)
+}
+
+func (p *resizeToFitPaddedFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	b := p.Bounds(src.Bounds())
+	w, h := b.Dx(), b.Dy()
+
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	fitw, fith := fitSize(src.Bounds().Dx(), src.Bounds().Dy(), w, h, true)
+	// Keep at least a single row or column of the image for the extreme aspect ratios.
+	fitw, fith = maxint(fitw, 1), maxint(fith, 1)
+	fitted := createTempImage(image.Rect(0, 0, fitw, fith))
+	Resize(fitw, fith, p.resampling).Draw(fitted, src, options)
+
+	pt := anchorPt(b, fitw, fith, p.anchor)
+	if p.background != nil {
+		p.background.fill(dst, src, fitted, pt, p.resampling, options)
+	}
+
+	dstb := dst.Bounds()
+	pixGetter := newPixelGetter(fitted)
+	pixSetter := newPixelSetter(dst)
+	parallelize(options.Parallelization, 0, fith, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < fitw; x++ {
+				pixSetter.setPixel(dstb.Min.X+pt.X+x, dstb.Min.Y+pt.Y+y, pixGetter.getPixel(x, y))
+			}
+		}
+	})
+}
+
+// ResizeToFitPadded creates a filter that resizes an image to the largest size that fits within the specified
+// dimensions while preserving the aspect ratio, then places it at the specified anchor point within
+// a width x height canvas. The remaining areas are filled using the specified background
+// (see ColorBackground, BlurBackground and EdgeBackground). If the background is nil, they are left untouched.
+// Unlike ResizeToFit, the output always has the exact specified dimensions and small images are upscaled.
+// The resampling parameter can be any of the predefined resampling filters (e.g. LanczosResampling, CubicResampling,
+// LinearResampling, BoxResampling, NearestNeighborResampling) or a custom filter created using NewResampling.
+//
+// Example:
+//
+//	// Letterbox the src image into a 1280x720 frame with black bars.
+//	g := gift.New(
+//		gift.ResizeToFitPadded(1280, 720, gift.LanczosResampling, gift.CenterAnchor, gift.ColorBackground(color.Black)),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ResizeToFitPadded(width, height int, resampling Resampling, anchor Anchor, background Background) Filter {
+	return &resizeToFitPaddedFilter{
+		width:      width,
+		height:     height,
+		resampling: resampling,
+		anchor:     anchor,
+		background: background,
+	}
+}
+
 func init() {
 	// Nearest neighbor resampling filter.
 	NearestNeighborResampling = resamp{
@@ -459,4 +817,121 @@ func init() {
 			return 0
 		},
 	}
+
+	// Lanczos resampling filter (2 lobes).
+	Lanczos2Resampling = resamp{
+		name:    "Lanczos2Resampling",
+		support: 2,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 2 {
+				return sinc(x) * sinc(x/2)
+			}
+			return 0
+		},
+	}
+
+	// Lanczos resampling filter (4 lobes).
+	Lanczos4Resampling = resamp{
+		name:    "Lanczos4Resampling",
+		support: 4,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 4 {
+				return sinc(x) * sinc(x/4)
+			}
+			return 0
+		},
+	}
+
+	// Mitchell-Netravali resampling filter.
+	MitchellResampling = resamp{
+		name:    "MitchellResampling",
+		support: 2,
+		kernel: func(x float32) float32 {
+			return bcspline(x, 1.0/3, 1.0/3)
+		},
+	}
+
+	// Cubic B-spline resampling filter.
+	BSplineResampling = resamp{
+		name:    "BSplineResampling",
+		support: 2,
+		kernel: func(x float32) float32 {
+			return bcspline(x, 1, 0)
+		},
+	}
+
+	// Hermite resampling filter.
+	HermiteResampling = resamp{
+		name:    "HermiteResampling",
+		support: 1,
+		kernel: func(x float32) float32 {
+			return bcspline(x, 0, 0)
+		},
+	}
+
+	// Hann windowed sinc resampling filter.
+	HannResampling = resamp{
+		name:    "HannResampling",
+		support: 3,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 3 {
+				return sinc(x) * (0.5 + 0.5*cosf32(math.Pi*x/3))
+			}
+			return 0
+		},
+	}
+
+	// Hamming windowed sinc resampling filter.
+	HammingResampling = resamp{
+		name:    "HammingResampling",
+		support: 3,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 3 {
+				return sinc(x) * (0.54 + 0.46*cosf32(math.Pi*x/3))
+			}
+			return 0
+		},
+	}
+
+	// Blackman windowed sinc resampling filter.
+	BlackmanResampling = resamp{
+		name:    "BlackmanResampling",
+		support: 3,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 3 {
+				return sinc(x) * (0.42 + 0.5*cosf32(math.Pi*x/3) + 0.08*cosf32(2*math.Pi*x/3))
+			}
+			return 0
+		},
+	}
+
+	// Gaussian resampling filter.
+	GaussianResampling = resamp{
+		name:    "GaussianResampling",
+		support: 2,
+		kernel: func(x float32) float32 {
+			if x < 0 {
+				x = -x
+			}
+			if x < 2 {
+				return expf32(-2 * x * x)
+			}
+			return 0
+		},
+	}
 }
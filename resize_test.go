@@ -2,7 +2,9 @@ package gift
 
 import (
 	"bytes"
+	"fmt"
 	"image"
+	"image/color"
 	"testing"
 )
 
@@ -32,6 +34,15 @@ func TestResize(t *testing.T) {
 		LinearResampling,
 		CubicResampling,
 		LanczosResampling,
+		Lanczos2Resampling,
+		Lanczos4Resampling,
+		MitchellResampling,
+		BSplineResampling,
+		HermiteResampling,
+		HannResampling,
+		HammingResampling,
+		BlackmanResampling,
+		GaussianResampling,
 	}
 	for _, prlz := range []bool{true, false} {
 		for _, z := range sz {
@@ -181,6 +192,122 @@ func TestResize(t *testing.T) {
 	}
 }
 
+func TestResampWeights(t *testing.T) {
+	rfilters := []Resampling{
+		BoxResampling,
+		LinearResampling,
+		CubicResampling,
+		LanczosResampling,
+		Lanczos2Resampling,
+		Lanczos4Resampling,
+		MitchellResampling,
+		BSplineResampling,
+		HermiteResampling,
+		HannResampling,
+		HammingResampling,
+		BlackmanResampling,
+		GaussianResampling,
+	}
+	sizes := []struct{ src, dst int }{
+		{1, 1}, {1, 5}, {5, 1}, {10, 3}, {3, 10}, {100, 37}, {37, 100}, {64, 64},
+	}
+	for _, r := range rfilters {
+		if r.Support() <= 0 {
+			t.Errorf("%s: unexpected support %v", r, r.Support())
+		}
+		if r.Kernel(r.Support()+0.01) != 0 || r.Kernel(-r.Support()-0.01) != 0 {
+			t.Errorf("%s: expected kernel to be 0 outside of the support", r)
+		}
+		for _, sz := range sizes {
+			weights := prepareResampWeights(sz.dst, sz.src, r)
+			if len(weights) != sz.dst {
+				t.Errorf("%s %d->%d: expected %d weight sets got %d", r, sz.src, sz.dst, sz.dst, len(weights))
+				continue
+			}
+			for i, ws := range weights {
+				var sum float32
+				for _, w := range ws {
+					if w.index < 0 || w.index >= sz.src {
+						t.Errorf("%s %d->%d: weight index %d out of range", r, sz.src, sz.dst, w.index)
+					}
+					sum += w.weight
+				}
+				if absf32(sum-1) > 1e-5 {
+					t.Errorf("%s %d->%d: weights of pixel %d sum to %v", r, sz.src, sz.dst, i, sum)
+				}
+			}
+		}
+	}
+
+	// Interpolating kernels are 1 at 0 and 0 at other integers.
+	for _, r := range []Resampling{LinearResampling, CubicResampling, LanczosResampling, Lanczos2Resampling,
+		Lanczos4Resampling, HermiteResampling, HannResampling, HammingResampling, BlackmanResampling} {
+		if absf32(r.Kernel(0)-1) > 1e-6 {
+			t.Errorf("%s: expected kernel(0) = 1 got %v", r, r.Kernel(0))
+		}
+		for x := float32(1); x < r.Support(); x++ {
+			if absf32(r.Kernel(x)) > 1e-6 || absf32(r.Kernel(-x)) > 1e-6 {
+				t.Errorf("%s: expected kernel(%v) = 0 got %v", r, x, r.Kernel(x))
+			}
+		}
+	}
+}
+
+func TestNewResampling(t *testing.T) {
+	triangle := NewResampling("triangle", 1, func(x float32) float32 {
+		if x < 0 {
+			x = -x
+		}
+		if x < 1 {
+			return 1 - x
+		}
+		return 0
+	})
+	if s := triangle.(fmt.Stringer).String(); s != "triangle" {
+		t.Errorf("unexpected name: %s", s)
+	}
+	if triangle.Support() != 1 {
+		t.Errorf("unexpected support: %v", triangle.Support())
+	}
+
+	src := image.NewGray(image.Rect(0, 0, 7, 5))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 7)
+	}
+	for _, sz := range []image.Point{{3, 2}, {14, 10}, {7, 9}} {
+		dst1 := image.NewGray(image.Rect(0, 0, sz.X, sz.Y))
+		Resize(sz.X, sz.Y, triangle).Draw(dst1, src, nil)
+		dst2 := image.NewGray(image.Rect(0, 0, sz.X, sz.Y))
+		Resize(sz.X, sz.Y, LinearResampling).Draw(dst2, src, nil)
+		if !bytes.Equal(dst1.Pix, dst2.Pix) {
+			t.Errorf("custom resampling %v: expected %v got %v", sz, dst2.Pix, dst1.Pix)
+		}
+	}
+
+	nearest := NewResampling("nearest", -1, nil)
+	if nearest.Support() != 0 {
+		t.Errorf("unexpected support: %v", nearest.Support())
+	}
+	dst1 := image.NewGray(image.Rect(0, 0, 3, 2))
+	Resize(3, 2, nearest).Draw(dst1, src, nil)
+	dst2 := image.NewGray(image.Rect(0, 0, 3, 2))
+	Resize(3, 2, NearestNeighborResampling).Draw(dst2, src, nil)
+	if !bytes.Equal(dst1.Pix, dst2.Pix) {
+		t.Errorf("custom nearest resampling: expected %v got %v", dst2.Pix, dst1.Pix)
+	}
+
+	// A nil kernel falls back to the nearest neighbor resampling regardless of the support.
+	nilKernel := NewResampling("nil", 2, nil)
+	if nilKernel.Support() != 0 {
+		t.Errorf("unexpected support: %v", nilKernel.Support())
+	}
+	dst1 = image.NewGray(image.Rect(0, 0, 3, 2))
+	Resize(3, 2, nilKernel).Draw(dst1, src, nil)
+	if !bytes.Equal(dst1.Pix, dst2.Pix) {
+		t.Errorf("nil kernel resampling: expected %v got %v", dst2.Pix, dst1.Pix)
+	}
+}
+
 func TestResizeToFit(t *testing.T) {
 	testData := []struct {
 		desc           string
@@ -291,6 +418,11 @@ func TestResizeToFit(t *testing.T) {
 			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
 		}
 	}
+
+	// The side that becomes thinner than half a pixel collapses to an empty image.
+	if b := ResizeToFit(100, 100, LinearResampling).Bounds(image.Rect(0, 0, 1, 10000)); !b.Empty() || b.Dy() != 100 {
+		t.Errorf("unexpected bounds %v", b)
+	}
 }
 
 func TestResizeToFill(t *testing.T) {
@@ -410,3 +542,267 @@ func TestResizeToFill(t *testing.T) {
 		}
 	}
 }
+
+func TestResizeToFitPadded(t *testing.T) {
+	testData := []struct {
+		desc           string
+		w, h           int
+		anchor         Anchor
+		bg             Background
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"resize to fit padded (4, 4, center, color)",
+			4, 4, CenterAnchor, ColorBackground(color.White),
+			image.Rect(-1, -1, 3, 1),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+			},
+			[]uint8{
+				0xff, 0xff, 0xff, 0xff,
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"resize to fit padded (4, 4, top, color)",
+			4, 4, TopAnchor, ColorBackground(color.White),
+			image.Rect(-1, -1, 3, 1),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"resize to fit padded (4, 4, center, edge)",
+			4, 4, CenterAnchor, EdgeBackground(),
+			image.Rect(-1, -1, 3, 1),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+				0x05, 0x06, 0x07, 0x08,
+			},
+		},
+		{
+			"resize to fit padded (3, 4, left, edge)",
+			3, 4, LeftAnchor, EdgeBackground(),
+			image.Rect(-1, -1, 0, 1),
+			image.Rect(0, 0, 3, 4),
+			[]uint8{
+				0x01,
+				0x05,
+			},
+			[]uint8{
+				0x01, 0x01, 0x01,
+				0x01, 0x01, 0x01,
+				0x05, 0x05, 0x05,
+				0x05, 0x05, 0x05,
+			},
+		},
+		{
+			"resize to fit padded (4, 3, bottom right, color) upscale",
+			4, 3, BottomRightAnchor, ColorBackground(color.Black),
+			image.Rect(-1, -1, 0, 1),
+			image.Rect(0, 0, 4, 3),
+			[]uint8{
+				0x01,
+				0x05,
+			},
+			[]uint8{
+				0x00, 0x00, 0x01, 0x01,
+				0x00, 0x00, 0x05, 0x05,
+				0x00, 0x00, 0x05, 0x05,
+			},
+		},
+		{
+			"resize to fit padded (2, 4, center, blur)",
+			2, 4, CenterAnchor, BlurBackground(1),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 2, 4),
+			[]uint8{
+				0x40, 0x40,
+			},
+			[]uint8{
+				0x40, 0x40,
+				0x40, 0x40,
+				0x40, 0x40,
+				0x40, 0x40,
+			},
+		},
+		{
+			"resize to fit padded (0, 4, center, color)",
+			0, 4, CenterAnchor, ColorBackground(color.White),
+			image.Rect(-1, -1, 3, 1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{
+				0x01, 0x02, 0x03, 0x04,
+				0x05, 0x06, 0x07, 0x08,
+			},
+			[]uint8{},
+		},
+		{
+			"resize to fit padded (2, 4, center, color) extreme aspect ratio",
+			2, 4, CenterAnchor, ColorBackground(color.White),
+			image.Rect(0, 0, 1, 12),
+			image.Rect(0, 0, 2, 4),
+			[]uint8{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c},
+			[]uint8{
+				0x02, 0xff,
+				0x05, 0xff,
+				0x08, 0xff,
+				0x0b, 0xff,
+			},
+		},
+		{
+			"resize to fit padded 0x0",
+			4, 4, CenterAnchor, ColorBackground(color.White),
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		f := ResizeToFitPadded(d.w, d.h, NearestNeighborResampling, d.anchor, d.bg)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+
+	// A nil background leaves the padded areas untouched.
+	src := image.NewGray(image.Rect(0, 0, 2, 1))
+	src.Pix = []uint8{0x01, 0x02}
+	dst := image.NewGray(image.Rect(0, 0, 2, 3))
+	for i := range dst.Pix {
+		dst.Pix[i] = 0x80
+	}
+	ResizeToFitPadded(2, 3, NearestNeighborResampling, CenterAnchor, nil).Draw(dst, src, nil)
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 2, 3), dst.Pix, []uint8{0x80, 0x80, 0x01, 0x02, 0x80, 0x80}) {
+		t.Errorf("test [nil background] failed: %#v", dst.Pix)
+	}
+
+	// A nil background color is transparent.
+	ndst := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := range ndst.Pix {
+		ndst.Pix[i] = 0x80
+	}
+	ResizeToFitPadded(2, 2, NearestNeighborResampling, TopAnchor, ColorBackground(nil)).Draw(ndst, src, nil)
+	want := []uint8{
+		0x01, 0x01, 0x01, 0xff, 0x02, 0x02, 0x02, 0xff,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+	if !checkBoundsAndPix(ndst.Bounds(), image.Rect(0, 0, 2, 2), ndst.Pix, want) {
+		t.Errorf("test [nil background color] failed: %#v", ndst.Pix)
+	}
+}
+
+func TestScale(t *testing.T) {
+	testData := []struct {
+		fx, fy     float32
+		srcb, dstb image.Rectangle
+	}{
+		{1, 1, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 10, 5)},
+		{0.5, 0.5, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 5, 3)},
+		{2, 0.5, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 20, 3)},
+		{0.01, 0.01, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 1, 1)},
+		{0, 1, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 0, 0)},
+		{1, -1, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 0, 0)},
+		{1, 1, image.Rect(-1, -1, -1, -1), image.Rect(0, 0, 0, 0)},
+	}
+
+	for _, d := range testData {
+		f := Scale(d.fx, d.fy, LinearResampling)
+		if b := f.Bounds(d.srcb); !b.Eq(d.dstb) {
+			t.Errorf("Scale(%v, %v) bounds for %v: expected %v got %v", d.fx, d.fy, d.srcb, d.dstb, b)
+		}
+	}
+
+	src := image.NewGray(image.Rect(-1, -1, 3, 1))
+	src.Pix = []uint8{
+		0x01, 0x02, 0x03, 0x04,
+		0x05, 0x06, 0x07, 0x08,
+	}
+	f := Scale(0.5, 1, NearestNeighborResampling)
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 2, 2), dst.Pix, []uint8{0x02, 0x04, 0x06, 0x08}) {
+		t.Errorf("Scale(0.5, 1) failed: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+}
+
+func TestResizeToMaxPixels(t *testing.T) {
+	testData := []struct {
+		maxPixels  int
+		srcb, dstb image.Rectangle
+	}{
+		{100, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 10, 5)},
+		{50, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 10, 5)},
+		{49, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 9, 5)},
+		{12, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 5, 2)},
+		{1, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 1, 1)},
+		{12000000, image.Rect(0, 0, 6000, 4000), image.Rect(0, 0, 4243, 2828)},
+		{12000000, image.Rect(0, 0, 10000, 1000), image.Rect(0, 0, 10000, 1000)},
+		{12000000, image.Rect(0, 0, 20000, 1000), image.Rect(0, 0, 15492, 774)},
+		{10, image.Rect(0, 0, 1000, 1), image.Rect(0, 0, 10, 1)},
+		{10, image.Rect(0, 0, 1, 1000), image.Rect(0, 0, 1, 10)},
+		{12, image.Rect(0, 0, 1000, 3), image.Rect(0, 0, 12, 1)},
+		{100, image.Rect(0, 0, 2, 100000), image.Rect(0, 0, 1, 100)},
+		{0, image.Rect(-1, -1, 9, 4), image.Rect(0, 0, 0, 0)},
+		{100, image.Rect(-1, -1, -1, -1), image.Rect(0, 0, 0, 0)},
+	}
+
+	for _, d := range testData {
+		f := ResizeToMaxPixels(d.maxPixels, LinearResampling)
+		b := f.Bounds(d.srcb)
+		if !b.Eq(d.dstb) {
+			t.Errorf("ResizeToMaxPixels(%d) bounds for %v: expected %v got %v", d.maxPixels, d.srcb, d.dstb, b)
+		}
+		if d.maxPixels > 0 && b.Dx()*b.Dy() > d.maxPixels {
+			t.Errorf("ResizeToMaxPixels(%d) bounds for %v: too many pixels %v", d.maxPixels, d.srcb, b)
+		}
+	}
+
+	src := image.NewGray(image.Rect(-1, -1, 3, 1))
+	src.Pix = []uint8{
+		0x01, 0x02, 0x03, 0x04,
+		0x05, 0x06, 0x07, 0x08,
+	}
+	f := ResizeToMaxPixels(2, NearestNeighborResampling)
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 2, 1), dst.Pix, []uint8{0x06, 0x08}) {
+		t.Errorf("ResizeToMaxPixels(2) failed: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+
+	// Images within the limit are copied unchanged.
+	f = ResizeToMaxPixels(8, LanczosResampling)
+	dst = image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 4, 2), dst.Pix, src.Pix) {
+		t.Errorf("ResizeToMaxPixels(8) failed: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+}
@@ -0,0 +1,356 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+)
+
+// seamMaskBias is the energy added to (or subtracted from) the masked pixels.
+const seamMaskBias = 1000
+
+// seamCarver holds the working state of the seam carving algorithm.
+// Only vertical seams are processed, horizontal seams are handled by transposing the image.
+type seamCarver struct {
+	w, h   int
+	pix    []pixel
+	bias   []float32
+	energy []float32
+	cum    []float64
+}
+
+func newSeamCarver(w, h int) *seamCarver {
+	return &seamCarver{
+		w:      w,
+		h:      h,
+		pix:    make([]pixel, w*h),
+		bias:   make([]float32, w*h),
+		energy: make([]float32, w*h),
+	}
+}
+
+// transpose returns a new seam carver with the rows and columns swapped.
+func (c *seamCarver) transpose() *seamCarver {
+	t := newSeamCarver(c.h, c.w)
+	for y := 0; y < c.h; y++ {
+		for x := 0; x < c.w; x++ {
+			t.pix[x*t.w+y] = c.pix[y*c.w+x]
+			t.bias[x*t.w+y] = c.bias[y*c.w+x]
+		}
+	}
+	return t
+}
+
+// updateEnergy calculates the gradient magnitude of the pixel at (x, y) using the Sobel operator.
+// Colors are premultiplied by alpha so the colors of fully transparent pixels don't contribute.
+func (c *seamCarver) updateEnergy(x, y int) {
+	var v [3][3][4]float32
+	for j := -1; j <= 1; j++ {
+		yy := minint(maxint(y+j, 0), c.h-1)
+		for i := -1; i <= 1; i++ {
+			xx := minint(maxint(x+i, 0), c.w-1)
+			px := c.pix[yy*c.w+xx]
+			v[j+1][i+1] = [4]float32{px.r * px.a, px.g * px.a, px.b * px.a, px.a}
+		}
+	}
+	var e float32
+	for k := 0; k < 4; k++ {
+		gx := v[0][2][k] + 2*v[1][2][k] + v[2][2][k] - v[0][0][k] - 2*v[1][0][k] - v[2][0][k]
+		gy := v[2][0][k] + 2*v[2][1][k] + v[2][2][k] - v[0][0][k] - 2*v[0][1][k] - v[0][2][k]
+		e += sqrtf32(gx*gx + gy*gy)
+	}
+	c.energy[y*c.w+x] = e
+}
+
+func (c *seamCarver) updateAllEnergy(parallel bool) {
+	parallelize(parallel, 0, c.h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < c.w; x++ {
+				c.updateEnergy(x, y)
+			}
+		}
+	})
+}
+
+// findSeam finds the vertical seam with the lowest total energy using dynamic programming.
+func (c *seamCarver) findSeam() []int {
+	w, h := c.w, c.h
+	if cap(c.cum) < w*h {
+		c.cum = make([]float64, w*h)
+	}
+	cum := c.cum[:w*h]
+
+	for x := 0; x < w; x++ {
+		cum[x] = float64(c.energy[x] + c.bias[x])
+	}
+	for y := 1; y < h; y++ {
+		row := y * w
+		prev := row - w
+		for x := 0; x < w; x++ {
+			m := cum[prev+x]
+			if x > 0 && cum[prev+x-1] < m {
+				m = cum[prev+x-1]
+			}
+			if x < w-1 && cum[prev+x+1] < m {
+				m = cum[prev+x+1]
+			}
+			cum[row+x] = m + float64(c.energy[row+x]+c.bias[row+x])
+		}
+	}
+
+	seam := make([]int, h)
+	last := (h - 1) * w
+	best := 0
+	for x := 1; x < w; x++ {
+		if cum[last+x] < cum[last+best] {
+			best = x
+		}
+	}
+	seam[h-1] = best
+	for y := h - 2; y >= 0; y-- {
+		row := y * w
+		x := seam[y+1]
+		best := x
+		if x > 0 && cum[row+x-1] < cum[row+best] {
+			best = x - 1
+		}
+		if x < w-1 && cum[row+x+1] < cum[row+best] {
+			best = x + 1
+		}
+		seam[y] = best
+	}
+	return seam
+}
+
+// removeSeam removes the given seam and updates the energy of the pixels around it.
+func (c *seamCarver) removeSeam(seam []int, index []int) {
+	w, h := c.w, c.h
+	nw := w - 1
+	for y := 0; y < h; y++ {
+		s := seam[y]
+		src := y * w
+		dst := y * nw
+		copy(c.pix[dst:], c.pix[src:src+s])
+		copy(c.pix[dst+s:], c.pix[src+s+1:src+w])
+		copy(c.bias[dst:], c.bias[src:src+s])
+		copy(c.bias[dst+s:], c.bias[src+s+1:src+w])
+		copy(c.energy[dst:], c.energy[src:src+s])
+		copy(c.energy[dst+s:], c.energy[src+s+1:src+w])
+		if index != nil {
+			copy(index[dst:], index[src:src+s])
+			copy(index[dst+s:], index[src+s+1:src+w])
+		}
+	}
+	c.w = nw
+	c.pix = c.pix[:nw*h]
+	c.bias = c.bias[:nw*h]
+	c.energy = c.energy[:nw*h]
+
+	// The Sobel operator only looks at direct neighbors and adjacent seam
+	// pixels are at most one column apart, so only a narrow band around
+	// the seam needs to be updated.
+	for y := 0; y < h; y++ {
+		for x := maxint(seam[y]-3, 0); x <= minint(seam[y]+2, nw-1); x++ {
+			c.updateEnergy(x, y)
+		}
+	}
+}
+
+// shrink removes n vertical seams.
+func (c *seamCarver) shrink(n int, parallel bool) {
+	c.updateAllEnergy(parallel)
+	for i := 0; i < n; i++ {
+		c.removeSeam(c.findSeam(), nil)
+	}
+}
+
+// enlarge inserts n vertical seams. The seams that would be removed first
+// are found on a copy of the image and then duplicated in the original.
+// At most half of the columns are duplicated at once to avoid stretching
+// the same seam over and over again.
+func (c *seamCarver) enlarge(n int, parallel bool) {
+	for n > 0 {
+		k := minint(n, maxint(c.w/2, 1))
+		n -= k
+
+		w, h := c.w, c.h
+		tmp := &seamCarver{
+			w:      w,
+			h:      h,
+			pix:    append([]pixel(nil), c.pix...),
+			bias:   append([]float32(nil), c.bias...),
+			energy: make([]float32, w*h),
+		}
+		index := make([]int, w*h)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				index[y*w+x] = x
+			}
+		}
+		dup := make([]bool, w*h)
+		tmp.updateAllEnergy(parallel)
+		for i := 0; i < k && tmp.w > 0; i++ {
+			seam := tmp.findSeam()
+			for y, x := range seam {
+				dup[y*w+index[y*tmp.w+x]] = true
+			}
+			tmp.removeSeam(seam, index)
+		}
+
+		nw := w + k
+		pix := make([]pixel, nw*h)
+		bias := make([]float32, nw*h)
+		parallelize(parallel, 0, h, func(start, stop int) {
+			for y := start; y < stop; y++ {
+				j := y * nw
+				for x := 0; x < w; x++ {
+					i := y*w + x
+					pix[j] = c.pix[i]
+					bias[j] = c.bias[i]
+					j++
+					if dup[i] {
+						px0 := c.pix[i]
+						px1 := c.pix[y*w+minint(x+1, w-1)]
+						pix[j] = pixel{
+							(px0.r + px1.r) / 2,
+							(px0.g + px1.g) / 2,
+							(px0.b + px1.b) / 2,
+							(px0.a + px1.a) / 2,
+						}
+						bias[j] = c.bias[i]
+						j++
+					}
+				}
+			}
+		})
+
+		c.w = nw
+		c.pix = pix
+		c.bias = bias
+		c.energy = make([]float32, nw*h)
+	}
+}
+
+// addMaskBias adds the given bias to the energy of the pixels marked in the mask.
+// The mask is aligned to the top-left corner of the source image.
+func (c *seamCarver) addMaskBias(mask image.Image, bias float32) {
+	if mask == nil {
+		return
+	}
+	mb := mask.Bounds()
+	pixGetter := newPixelGetter(mask)
+	for y := 0; y < minint(c.h, mb.Dy()); y++ {
+		for x := 0; x < minint(c.w, mb.Dx()); x++ {
+			px := pixGetter.getPixel(mb.Min.X+x, mb.Min.Y+y)
+			if (0.299*px.r+0.587*px.g+0.114*px.b)*px.a > 0.5 {
+				c.bias[y*c.w+x] += bias
+			}
+		}
+	}
+}
+
+// resize changes the number of columns to the given width.
+func (c *seamCarver) resize(width int, parallel bool) {
+	switch {
+	case width < c.w:
+		c.shrink(c.w-width, parallel)
+	case width > c.w:
+		c.enlarge(width-c.w, parallel)
+	}
+}
+
+type seamCarveFilter struct {
+	width, height   int
+	protect, remove image.Image
+}
+
+func (p *seamCarveFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h := p.width, p.height
+	if w <= 0 || h <= 0 || srcBounds.Empty() {
+		return image.Rect(0, 0, 0, 0)
+	}
+	return image.Rect(0, 0, w, h)
+}
+
+func (p *seamCarveFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+	w, h := srcb.Dx(), srcb.Dy()
+
+	if w <= 0 || h <= 0 || p.width <= 0 || p.height <= 0 {
+		return
+	}
+
+	if w == p.width && h == p.height {
+		copyimage(dst, src, options)
+		return
+	}
+
+	c := newSeamCarver(w, h)
+	pixGetter := newPixelGetter(src)
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < w; x++ {
+				c.pix[y*w+x] = pixGetter.getPixel(srcb.Min.X+x, srcb.Min.Y+y)
+			}
+		}
+	})
+	c.addMaskBias(p.protect, seamMaskBias)
+	c.addMaskBias(p.remove, -seamMaskBias)
+
+	c.resize(p.width, options.Parallelization)
+	if h != p.height {
+		c = c.transpose()
+		c.resize(p.height, options.Parallelization)
+		c = c.transpose()
+	}
+
+	pixSetter := newPixelSetter(dst)
+	parallelize(options.Parallelization, 0, c.h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < c.w; x++ {
+				pixSetter.setPixel(dstb.Min.X+x, dstb.Min.Y+y, c.pix[y*c.w+x])
+			}
+		}
+	})
+}
+
+// SeamCarve creates a filter that resizes an image to the specified width and height
+// using content-aware resizing (seam carving). Connected paths of pixels with the lowest
+// energy (gradient magnitude calculated using the Sobel operator) are removed or duplicated,
+// so the image can change its aspect ratio without distorting or cropping the important content.
+// The width is changed first, then the height.
+// If one of width or height is 0, the image bounds will be empty.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.SeamCarve(800, 600),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func SeamCarve(width, height int) Filter {
+	return &seamCarveFilter{
+		width:  width,
+		height: height,
+	}
+}
+
+// SeamCarveMasked creates a filter that resizes an image to the specified width and height
+// using content-aware resizing (seam carving), like SeamCarve, with optional masks.
+// Seams avoid the pixels marked in the protect mask and go through the pixels marked in the remove mask
+// whenever possible, so removing seams can be used to erase objects from the image.
+// Light opaque pixels of a mask mark the corresponding pixels of the image, dark or transparent pixels are unmarked.
+// The masks are aligned to the top-left corner of the image. Either of the masks can be nil.
+func SeamCarveMasked(width, height int, protect, remove image.Image) Filter {
+	return &seamCarveFilter{
+		width:   width,
+		height:  height,
+		protect: protect,
+		remove:  remove,
+	}
+}
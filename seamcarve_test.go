@@ -0,0 +1,189 @@
+package gift
+
+import (
+	"image"
+	"testing"
+)
+
+func TestSeamCarve(t *testing.T) {
+	testData := []struct {
+		desc           string
+		w, h           int
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"seam carve (4, 2) shrink",
+			4, 2,
+			image.Rect(-1, -1, 5, 1),
+			image.Rect(0, 0, 4, 2),
+			[]uint8{
+				0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+				0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+			},
+			[]uint8{
+				0x80, 0x00, 0x00, 0x80,
+				0x80, 0x00, 0x00, 0x80,
+			},
+		},
+		{
+			"seam carve (8, 2) enlarge",
+			8, 2,
+			image.Rect(-1, -1, 5, 1),
+			image.Rect(0, 0, 8, 2),
+			[]uint8{
+				0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+				0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+			},
+			[]uint8{
+				0x80, 0x80, 0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+				0x80, 0x80, 0x80, 0x80, 0x80, 0x00, 0x00, 0x80,
+			},
+		},
+		{
+			"seam carve (2, 4) shrink vertical",
+			2, 4,
+			image.Rect(-1, -1, 1, 5),
+			image.Rect(0, 0, 2, 4),
+			[]uint8{
+				0x80, 0x80,
+				0x80, 0x80,
+				0x80, 0x80,
+				0x00, 0x00,
+				0x00, 0x00,
+				0x80, 0x80,
+			},
+			[]uint8{
+				0x80, 0x80,
+				0x00, 0x00,
+				0x00, 0x00,
+				0x80, 0x80,
+			},
+		},
+		{
+			"seam carve (3, 3) same size",
+			3, 3,
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 3, 3),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09,
+			},
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09,
+			},
+		},
+		{
+			"seam carve (0, 3)",
+			0, 3,
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+				0x07, 0x08, 0x09,
+			},
+			[]uint8{},
+		},
+		{
+			"seam carve 0x0",
+			3, 3,
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		f := SeamCarve(d.w, d.h)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestSeamCarveLarge(t *testing.T) {
+	// A dark square on a flat background must survive resizing in both directions.
+	src := image.NewGray(image.Rect(0, 0, 40, 30))
+	for i := range src.Pix {
+		src.Pix[i] = 0xc0
+	}
+	for y := 10; y < 20; y++ {
+		for x := 15; x < 25; x++ {
+			src.Pix[y*src.Stride+x] = 0x20
+		}
+	}
+
+	for _, size := range []image.Point{{20, 20}, {60, 40}, {30, 45}} {
+		for _, parallel := range []bool{false, true} {
+			f := SeamCarve(size.X, size.Y)
+			dst := image.NewGray(f.Bounds(src.Bounds()))
+			f.Draw(dst, src, &Options{Parallelization: parallel})
+			if !dst.Bounds().Eq(image.Rectangle{Max: size}) {
+				t.Errorf("seam carve %v: bad bounds %v", size, dst.Bounds())
+				continue
+			}
+			dark := 0
+			for _, v := range dst.Pix {
+				if v == 0x20 {
+					dark++
+				}
+			}
+			if dark != 100 {
+				t.Errorf("seam carve %v: expected 100 dark pixels, got %d", size, dark)
+			}
+		}
+	}
+}
+
+func TestSeamCarveMasked(t *testing.T) {
+	// A horizontal ramp where every column is distinct.
+	src := image.NewGray(image.Rect(0, 0, 8, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 8; x++ {
+			src.Pix[y*src.Stride+x] = uint8(x * 0x20)
+		}
+	}
+
+	column := image.NewGray(image.Rect(10, 10, 18, 14))
+	for y := 0; y < 4; y++ {
+		column.Pix[y*column.Stride+5] = 0xff
+	}
+
+	others := image.NewGray(image.Rect(0, 0, 8, 4))
+	for i := range others.Pix {
+		others.Pix[i] = 0xff - column.Pix[i]
+	}
+
+	testData := []struct {
+		desc            string
+		protect, remove image.Image
+	}{
+		{"remove", nil, column},
+		{"protect", others, nil},
+		{"protect and remove", others, column},
+	}
+
+	for _, d := range testData {
+		f := SeamCarveMasked(7, 4, d.protect, d.remove)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		want := []uint8{0x00, 0x20, 0x40, 0x60, 0x80, 0xc0, 0xe0}
+		for y := 0; y < 4; y++ {
+			row := dst.Pix[y*dst.Stride : y*dst.Stride+7]
+			if string(row) != string(want) {
+				t.Errorf("test [%s] failed: row %d: %#v", d.desc, y, row)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+const (
+	smartCropSteps         = 32
+	smartCropEntropyBins   = 32
+	smartCropEntropySample = 64
+
+	smartCropSkinWeight       = 1.8
+	smartCropSaturationWeight = 0.3
+	smartCropEntropyWeight    = 0.2
+)
+
+// skinScore returns a value in range [0, 1] that indicates how close the color is to a skin tone.
+func skinScore(r, g, b, l float32) float32 {
+	if l < 0.2 {
+		return 0
+	}
+	mag := sqrtf32(r*r + g*g + b*b)
+	if mag == 0 {
+		return 0
+	}
+	// Normalized skin color (0.78, 0.57, 0.44).
+	dr := r/mag - 0.7348
+	dg := g/mag - 0.5369
+	db := b/mag - 0.4145
+	s := 1 - sqrtf32(dr*dr+dg*dg+db*db)
+	if s < 0.8 {
+		return 0
+	}
+	return (s - 0.8) / 0.2
+}
+
+// saturationScore returns a value in range [0, 1] that indicates how saturated the color is.
+func saturationScore(r, g, b float32) float32 {
+	_, s, l := convertRGBToHSL(r, g, b)
+	if l < 0.05 || l > 0.9 || s < 0.4 {
+		return 0
+	}
+	return (s - 0.4) / 0.6
+}
+
+// newSmartCropScorer returns the default scoring function used by SmartCrop.
+// It precalculates the importance of each pixel (edge density, skin tones and saturation)
+// as a summed-area table so the importance of any window can be calculated in constant time.
+func newSmartCropScorer(src image.Image, options *Options) func(src image.Image, rect image.Rectangle) float32 {
+	srcb := src.Bounds()
+	w, h := srcb.Dx(), srcb.Dy()
+	pixGetter := newPixelGetter(src)
+
+	lum := make([]float32, w*h)
+	imp := make([]float32, w*h)
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < w; x++ {
+				px := pixGetter.getPixel(srcb.Min.X+x, srcb.Min.Y+y)
+				l := minf32(maxf32(0.299*px.r+0.587*px.g+0.114*px.b, 0), 1)
+				lum[y*w+x] = l
+				imp[y*w+x] = (smartCropSkinWeight*skinScore(px.r, px.g, px.b, l) +
+					smartCropSaturationWeight*saturationScore(px.r, px.g, px.b)) * px.a
+			}
+		}
+	})
+
+	// Edge density is the gradient magnitude of the luminance calculated using the Sobel operator.
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			y0, y1 := maxint(y-1, 0), minint(y+1, h-1)
+			for x := 0; x < w; x++ {
+				x0, x1 := maxint(x-1, 0), minint(x+1, w-1)
+				gx := lum[y0*w+x1] + 2*lum[y*w+x1] + lum[y1*w+x1] - lum[y0*w+x0] - 2*lum[y*w+x0] - lum[y1*w+x0]
+				gy := lum[y1*w+x0] + 2*lum[y1*w+x] + lum[y1*w+x1] - lum[y0*w+x0] - 2*lum[y0*w+x] - lum[y0*w+x1]
+				imp[y*w+x] += minf32(sqrtf32(gx*gx+gy*gy), 1)
+			}
+		}
+	})
+
+	sw := w + 1
+	sum := make([]float64, sw*(h+1))
+	for y := 0; y < h; y++ {
+		var rs float64
+		for x := 0; x < w; x++ {
+			rs += float64(imp[y*w+x])
+			sum[(y+1)*sw+x+1] = sum[y*sw+x+1] + rs
+		}
+	}
+
+	maxEntropy := math.Log(smartCropEntropyBins)
+
+	return func(_ image.Image, rect image.Rectangle) float32 {
+		r := rect.Sub(srcb.Min).Intersect(image.Rect(0, 0, w, h))
+		if r.Empty() {
+			return 0
+		}
+		n := float64(r.Dx() * r.Dy())
+		s := sum[r.Max.Y*sw+r.Max.X] - sum[r.Min.Y*sw+r.Max.X] - sum[r.Max.Y*sw+r.Min.X] + sum[r.Min.Y*sw+r.Min.X]
+
+		// The luminance entropy is estimated using a subsample of the window pixels.
+		var hist [smartCropEntropyBins]int
+		stepx := maxint(r.Dx()/smartCropEntropySample, 1)
+		stepy := maxint(r.Dy()/smartCropEntropySample, 1)
+		cnt := 0
+		for y := r.Min.Y; y < r.Max.Y; y += stepy {
+			for x := r.Min.X; x < r.Max.X; x += stepx {
+				hist[minint(int(lum[y*w+x]*smartCropEntropyBins), smartCropEntropyBins-1)]++
+				cnt++
+			}
+		}
+		var entropy float64
+		for _, c := range hist {
+			if c > 0 {
+				p := float64(c) / float64(cnt)
+				entropy -= p * math.Log(p)
+			}
+		}
+
+		return float32(s/n + smartCropEntropyWeight*entropy/maxEntropy)
+	}
+}
+
+// smartCropPositions returns the candidate window offsets along one axis.
+func smartCropPositions(size, window int) []int {
+	span := size - window
+	if span <= 0 {
+		return []int{0}
+	}
+	step := maxint(span/smartCropSteps, 1)
+	var pos []int
+	for i := 0; i < span; i += step {
+		pos = append(pos, i)
+	}
+	return append(pos, span)
+}
+
+// SmartCropRect returns the rectangle within the src image bounds that would be cropped by the SmartCrop filter
+// (or by the SmartCropFunc filter if the score function is not nil) when applied to the src image.
+// If the options parameter is nil, the default options are used.
+func SmartCropRect(src image.Image, width, height int, score func(src image.Image, rect image.Rectangle) float32, options *Options) image.Rectangle {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	w, h := minint(width, srcb.Dx()), minint(height, srcb.Dy())
+	if w <= 0 || h <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+
+	if score == nil {
+		score = newSmartCropScorer(src, options)
+	}
+
+	var best image.Rectangle
+	var bestScore float32
+	for _, y := range smartCropPositions(srcb.Dy(), h) {
+		for _, x := range smartCropPositions(srcb.Dx(), w) {
+			r := image.Rect(x, y, x+w, y+h).Add(srcb.Min)
+			s := score(src, r)
+			if best.Empty() || s > bestScore {
+				best = r
+				bestScore = s
+			}
+		}
+	}
+	return best
+}
+
+type smartCropFilter struct {
+	w, h  int
+	score func(src image.Image, rect image.Rectangle) float32
+}
+
+func (p *smartCropFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	w, h := minint(p.w, srcBounds.Dx()), minint(p.h, srcBounds.Dy())
+	if w <= 0 || h <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+	return image.Rect(0, 0, w, h)
+}
+
+func (p *smartCropFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	r := SmartCropRect(src, p.w, p.h, p.score, options)
+	if r.Empty() {
+		return
+	}
+	Crop(r).Draw(dst, src, options)
+}
+
+// SmartCrop creates a filter that crops an image to the specified size choosing the most interesting region
+// instead of a fixed anchor point. Candidate regions are scored by edge density, skin tones, saturation
+// and luminance entropy.
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.Resize(0, 300, gift.LanczosResampling),
+//		gift.SmartCrop(300, 300),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func SmartCrop(width, height int) Filter {
+	return &smartCropFilter{
+		w: width,
+		h: height,
+	}
+}
+
+// SmartCropFunc creates a filter that crops an image to the specified size choosing the candidate region
+// with the highest score returned by the specified scoring function. The function is called with the
+// source image and a candidate rectangle within its bounds.
+func SmartCropFunc(width, height int, score func(src image.Image, rect image.Rectangle) float32) Filter {
+	return &smartCropFilter{
+		w:     width,
+		h:     height,
+		score: score,
+	}
+}
@@ -0,0 +1,129 @@
+package gift
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestSmartCrop(t *testing.T) {
+	newImage := func() *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(-10, -10, 70, 50))
+		for y := -10; y < 50; y++ {
+			for x := -10; x < 70; x++ {
+				img.Set(x, y, color.NRGBA{0x80, 0x80, 0x80, 0xff})
+			}
+		}
+		return img
+	}
+
+	// A textured area in the bottom-right part of the image.
+	textured := newImage()
+	for y := 25; y < 40; y++ {
+		for x := 45; x < 60; x++ {
+			if (x+y)%2 == 0 {
+				textured.Set(x, y, color.NRGBA{0x00, 0x00, 0x00, 0xff})
+			}
+		}
+	}
+
+	// A flat skin-colored area in the top-left part of the image.
+	skin := newImage()
+	for y := -5; y < 10; y++ {
+		for x := -5; x < 10; x++ {
+			skin.Set(x, y, color.NRGBA{0xd0, 0x98, 0x75, 0xff})
+		}
+	}
+
+	// A flat saturated area in the top-right part of the image.
+	saturated := newImage()
+	for y := -5; y < 10; y++ {
+		for x := 45; x < 60; x++ {
+			saturated.Set(x, y, color.NRGBA{0x20, 0x40, 0xf0, 0xff})
+		}
+	}
+
+	testData := []struct {
+		desc string
+		img  image.Image
+		want image.Rectangle
+	}{
+		{"textured", textured, image.Rect(45, 25, 60, 40)},
+		{"skin", skin, image.Rect(-5, -5, 10, 10)},
+		{"saturated", saturated, image.Rect(45, -5, 60, 10)},
+	}
+
+	for _, d := range testData {
+		r := SmartCropRect(d.img, 20, 20, nil, nil)
+		if r.Dx() != 20 || r.Dy() != 20 || !r.In(d.img.Bounds()) {
+			t.Errorf("test [%s]: bad rectangle %v", d.desc, r)
+			continue
+		}
+		if r1 := SmartCropRect(d.img, 20, 20, nil, &Options{Parallelization: false}); !r1.Eq(r) {
+			t.Errorf("test [%s]: expected %v without parallelization got %v", d.desc, r, r1)
+		}
+		if !d.want.In(r) {
+			t.Errorf("test [%s]: expected %v to contain %v", d.desc, r, d.want)
+		}
+
+		f := SmartCrop(20, 20)
+		dst := image.NewNRGBA(f.Bounds(d.img.Bounds()))
+		f.Draw(dst, d.img, nil)
+		want := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+		Crop(r).Draw(want, d.img, nil)
+		if !checkBoundsAndPix(dst.Bounds(), want.Bounds(), dst.Pix, want.Pix) {
+			t.Errorf("test [%s]: filter result doesn't match the rectangle %v", d.desc, r)
+		}
+	}
+}
+
+func TestSmartCropFunc(t *testing.T) {
+	src := image.NewGray(image.Rect(-1, -1, 4, 3))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i)
+	}
+
+	// Prefer the bottom-right corner.
+	score := func(img image.Image, r image.Rectangle) float32 {
+		if img != src {
+			t.Errorf("unexpected image passed to the score function")
+		}
+		return float32(r.Min.X + r.Min.Y)
+	}
+
+	if r := SmartCropRect(src, 2, 2, score, nil); !r.Eq(image.Rect(2, 1, 4, 3)) {
+		t.Errorf("unexpected rectangle %v", r)
+	}
+
+	f := SmartCropFunc(2, 2, score)
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 2, 2), dst.Pix, []uint8{13, 14, 18, 19}) {
+		t.Errorf("unexpected result: %#v, %#v", dst.Bounds(), dst.Pix)
+	}
+}
+
+func TestSmartCropBounds(t *testing.T) {
+	testData := []struct {
+		w, h       int
+		srcb, dstb image.Rectangle
+	}{
+		{2, 2, image.Rect(-1, -1, 4, 3), image.Rect(0, 0, 2, 2)},
+		{10, 2, image.Rect(-1, -1, 4, 3), image.Rect(0, 0, 5, 2)},
+		{10, 10, image.Rect(-1, -1, 4, 3), image.Rect(0, 0, 5, 4)},
+		{0, 2, image.Rect(-1, -1, 4, 3), image.Rect(0, 0, 0, 0)},
+		{2, 2, image.Rect(0, 0, 0, 0), image.Rect(0, 0, 0, 0)},
+	}
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		f := SmartCrop(d.w, d.h)
+		if b := f.Bounds(d.srcb); !b.Eq(d.dstb) {
+			t.Errorf("SmartCrop(%d, %d) bounds for %v: expected %v got %v", d.w, d.h, d.srcb, d.dstb, b)
+		}
+		dst := image.NewGray(f.Bounds(d.srcb))
+		f.Draw(dst, src, nil)
+		if r := SmartCropRect(src, d.w, d.h, nil, nil); r.Dx() != d.dstb.Dx() || r.Dy() != d.dstb.Dy() {
+			t.Errorf("SmartCropRect(%d, %d) for %v: unexpected rectangle %v", d.w, d.h, d.srcb, r)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+type thresholdMode int
+
+const (
+	thresholdOtsu thresholdMode = iota
+	thresholdMean
+	thresholdNiblack
+	thresholdSauvola
+)
+
+type autoThresholdFilter struct {
+	mode  thresholdMode
+	ksize int
+	k     float32
+}
+
+func (p *autoThresholdFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+	return
+}
+
+// otsuThreshold calculates the threshold that minimizes the intra-class variance of the histogram.
+func otsuThreshold(hist []int) float32 {
+	var total, sum float64
+	for i, n := range hist {
+		total += float64(n)
+		sum += float64(i) * float64(n)
+	}
+	if total == 0 {
+		return 0.5
+	}
+
+	var sumB, wB, maxVar float64
+	best := 0
+	for i, n := range hist {
+		wB += float64(n)
+		if wB == 0 {
+			continue
+		}
+		wF := total - wB
+		if wF == 0 {
+			break
+		}
+		sumB += float64(i) * float64(n)
+		mB := sumB / wB
+		mF := (sum - sumB) / wF
+		v := wB * wF * (mB - mF) * (mB - mF)
+		if v > maxVar {
+			maxVar = v
+			best = i
+		}
+	}
+	return (float32(best) + 0.5) / float32(len(hist)-1)
+}
+
+func (p *autoThresholdFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+	w, h := srcb.Dx(), srcb.Dy()
+
+	if w <= 0 || h <= 0 {
+		return
+	}
+
+	pixGetter := newPixelGetter(src)
+	pixSetter := newPixelSetter(dst)
+
+	// Calculate pixel luminances.
+	lum := make([]float32, w*h)
+	alpha := make([]float32, w*h)
+	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := srcb.Min.X; x < srcb.Max.X; x++ {
+				px := pixGetter.getPixel(x, y)
+				i := (y-srcb.Min.Y)*w + x - srcb.Min.X
+				lum[i] = minf32(maxf32(0.299*px.r+0.587*px.g+0.114*px.b, 0), 1)
+				alpha[i] = px.a
+			}
+		}
+	})
+
+	var threshold func(x, y int) float32
+
+	if p.mode == thresholdOtsu {
+		hist := make([]int, 256)
+		for _, v := range lum {
+			hist[int(v*255+0.5)]++
+		}
+		t := otsuThreshold(hist)
+		threshold = func(x, y int) float32 {
+			return t
+		}
+	} else {
+		// Summed-area tables of the luminance and of its square.
+		sw := w + 1
+		sum := make([]float64, sw*(h+1))
+		sqsum := make([]float64, sw*(h+1))
+		for y := 0; y < h; y++ {
+			var rs, rsq float64
+			for x := 0; x < w; x++ {
+				v := float64(lum[y*w+x])
+				rs += v
+				rsq += v * v
+				sum[(y+1)*sw+x+1] = sum[y*sw+x+1] + rs
+				sqsum[(y+1)*sw+x+1] = sqsum[y*sw+x+1] + rsq
+			}
+		}
+
+		ksize := p.ksize
+		if ksize%2 == 0 {
+			ksize--
+		}
+		// A single pixel is not a neighborhood, the smallest one is 3x3.
+		kradius := maxint(ksize, 3) / 2
+		threshold = func(x, y int) float32 {
+			x0, x1 := maxint(x-kradius, 0), minint(x+kradius+1, w)
+			y0, y1 := maxint(y-kradius, 0), minint(y+kradius+1, h)
+			n := float64((x1 - x0) * (y1 - y0))
+			s := sum[y1*sw+x1] - sum[y0*sw+x1] - sum[y1*sw+x0] + sum[y0*sw+x0]
+			sq := sqsum[y1*sw+x1] - sqsum[y0*sw+x1] - sqsum[y1*sw+x0] + sqsum[y0*sw+x0]
+			mean := s / n
+			std := math.Sqrt(math.Max(sq/n-mean*mean, 0))
+			switch p.mode {
+			case thresholdNiblack:
+				return float32(mean + float64(p.k)*std)
+			case thresholdSauvola:
+				return float32(mean * (1 + float64(p.k)*(std/0.5-1)))
+			default:
+				return float32(mean) - p.k
+			}
+		}
+	}
+
+	parallelize(options.Parallelization, 0, h, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < w; x++ {
+				i := y*w + x
+				var v float32
+				if lum[i] > threshold(x, y) {
+					v = 1
+				}
+				pixSetter.setPixel(dstb.Min.X+x, dstb.Min.Y+y, pixel{v, v, v, alpha[i]})
+			}
+		}
+	})
+}
+
+// ThresholdOtsu creates a filter that applies black/white thresholding to the image
+// using a global threshold selected automatically by Otsu's method.
+func ThresholdOtsu() Filter {
+	return &autoThresholdFilter{
+		mode: thresholdOtsu,
+	}
+}
+
+// ThresholdAdaptiveMean creates a filter that applies adaptive black/white thresholding to the image.
+// A pixel becomes white if it is brighter than the mean of its neighborhood minus the offset.
+// The ksize parameter is the neighborhood size. It must be an odd integer greater than 1 (for example: 15, 25, 51),
+// even values are decreased by one and the values less than 3 are treated as 3.
+// The offset parameter must be in range (-100, 100), typically between 0 and 10.
+func ThresholdAdaptiveMean(ksize int, offset float32) Filter {
+	return &autoThresholdFilter{
+		mode:  thresholdMean,
+		ksize: ksize,
+		k:     minf32(maxf32(offset, -100), 100) / 100,
+	}
+}
+
+// ThresholdNiblack creates a filter that applies adaptive black/white thresholding to the image
+// using Niblack's method. The threshold is the local mean plus k times the local standard deviation.
+// The ksize parameter is the neighborhood size. It must be an odd integer greater than 1 (for example: 15, 25, 51),
+// even values are decreased by one and the values less than 3 are treated as 3.
+// The k parameter is typically -0.2.
+func ThresholdNiblack(ksize int, k float32) Filter {
+	return &autoThresholdFilter{
+		mode:  thresholdNiblack,
+		ksize: ksize,
+		k:     k,
+	}
+}
+
+// ThresholdSauvola creates a filter that applies adaptive black/white thresholding to the image
+// using Sauvola's method. It works well for document images with uneven lighting.
+// The ksize parameter is the neighborhood size. It must be an odd integer greater than 1 (for example: 15, 25, 51),
+// even values are decreased by one and the values less than 3 are treated as 3.
+// The k parameter is typically in range (0.2, 0.5).
+//
+// Example:
+//
+//	g := gift.New(
+//		gift.ThresholdSauvola(25, 0.34),
+//	)
+//	dst := image.NewGray(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ThresholdSauvola(ksize int, k float32) Filter {
+	return &autoThresholdFilter{
+		mode:  thresholdSauvola,
+		ksize: ksize,
+		k:     k,
+	}
+}
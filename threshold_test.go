@@ -0,0 +1,153 @@
+package gift
+
+import (
+	"bytes"
+	"image"
+	"testing"
+)
+
+func TestOtsuThreshold(t *testing.T) {
+	testData := []struct {
+		hist []int
+		want float32
+	}{
+		{[]int{0, 0, 0, 0}, 0.5},
+		{[]int{5, 0, 0, 5}, 0.5 / 3},
+		{[]int{5, 5, 0, 5}, 1.5 / 3},
+		{[]int{1, 0, 9, 10}, 2.5 / 3},
+	}
+	for _, d := range testData {
+		got := otsuThreshold(d.hist)
+		if absf32(got-d.want) > 1e-6 {
+			t.Errorf("otsuThreshold(%v): expected %v got %v", d.hist, d.want, got)
+		}
+	}
+}
+
+func TestThresholdOtsu(t *testing.T) {
+	testData := []struct {
+		desc           string
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"threshold otsu",
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x10, 0x20, 0x18, 0x90, 0xa0, 0x98},
+			[]uint8{0x00, 0x00, 0x00, 0xff, 0xff, 0xff},
+		},
+		{
+			"threshold otsu dark",
+			image.Rect(-1, -1, 5, 0),
+			image.Rect(0, 0, 6, 1),
+			[]uint8{0x01, 0x02, 0x01, 0x09, 0x0a, 0x09},
+			[]uint8{0x00, 0x00, 0x00, 0xff, 0xff, 0xff},
+		},
+		{
+			"threshold otsu 0x0",
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		f := ThresholdOtsu()
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestThresholdAdaptive(t *testing.T) {
+	// A horizontal lighting gradient with a dark mark on each side.
+	src := image.NewGray(image.Rect(-1, -1, 19, 4))
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 20; x++ {
+			v := 0x20 + x*10
+			if y == 2 && (x == 3 || x == 16) {
+				v /= 2
+			}
+			src.Pix[y*src.Stride+x] = uint8(v)
+		}
+	}
+
+	filters := map[string]Filter{
+		"mean":    ThresholdAdaptiveMean(5, 2),
+		"niblack": ThresholdNiblack(5, -0.2),
+		"sauvola": ThresholdSauvola(5, 0.2),
+	}
+
+	for name, f := range filters {
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !dst.Bounds().Eq(image.Rect(0, 0, 20, 5)) {
+			t.Errorf("test [%s] bad bounds: %v", name, dst.Bounds())
+			continue
+		}
+		for _, x := range []int{3, 16} {
+			if dst.Pix[2*dst.Stride+x] != 0x00 {
+				t.Errorf("test [%s]: expected mark at %d to be black", name, x)
+			}
+			if dst.Pix[0*dst.Stride+x] != 0xff {
+				t.Errorf("test [%s]: expected background at %d to be white", name, x)
+			}
+		}
+	}
+
+	// A global threshold can't separate both marks from the background.
+	f := ThresholdOtsu()
+	dst := image.NewGray(f.Bounds(src.Bounds()))
+	f.Draw(dst, src, nil)
+	if dst.Pix[2*dst.Stride+16] == 0x00 && dst.Pix[0*dst.Stride+3] == 0xff {
+		t.Errorf("test [otsu]: unexpected separation of unevenly lit marks")
+	}
+}
+
+func TestThresholdAdaptiveKsize(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 37 % 256)
+	}
+
+	draw := func(f Filter) []uint8 {
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		return dst.Pix
+	}
+
+	filters := map[string]func(ksize int) Filter{
+		"mean": func(ksize int) Filter {
+			return ThresholdAdaptiveMean(ksize, 0)
+		},
+		"niblack": func(ksize int) Filter {
+			return ThresholdNiblack(ksize, -0.2)
+		},
+		"sauvola": func(ksize int) Filter {
+			return ThresholdSauvola(ksize, 0.2)
+		},
+	}
+
+	for name, fn := range filters {
+		want3, want5 := draw(fn(3)), draw(fn(5))
+		if bytes.Count(want3, []byte{0xff}) == 0 {
+			t.Errorf("test [%s]: expected some white pixels", name)
+		}
+		for _, ksize := range []int{-3, 0, 1, 2} {
+			if got := draw(fn(ksize)); !bytes.Equal(got, want3) {
+				t.Errorf("test [%s] ksize %d: expected %v got %v", name, ksize, want3, got)
+			}
+		}
+		if got := draw(fn(6)); !bytes.Equal(got, want5) {
+			t.Errorf("test [%s] ksize 6: expected %v got %v", name, want5, got)
+		}
+	}
+}
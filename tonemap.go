@@ -0,0 +1,258 @@
+package gift
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"sync"
+)
+
+type toneMapOperator int
+
+const (
+	tmReinhard toneMapOperator = iota
+	tmReinhardLocal
+	tmACES
+	tmHable
+	tmDrago
+)
+
+// luminance returns the relative luminance of a linear RGB color (Rec. 709 primaries).
+func luminance(r, g, b float32) float32 {
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+func hable(x float32) float32 {
+	const (
+		a = 0.15
+		b = 0.50
+		c = 0.10
+		d = 0.20
+		e = 0.02
+		f = 0.30
+	)
+	return ((x*(a*x+c*b) + d*e) / (x*(a*x+b) + d*f)) - e/f
+}
+
+func aces(x float32) float32 {
+	const (
+		a = 2.51
+		b = 0.03
+		c = 2.43
+		d = 0.59
+		e = 0.14
+	)
+	return minf32(maxf32((x*(a*x+b))/(x*(c*x+d)+e), 0), 1)
+}
+
+type toneMapFilter struct {
+	op       toneMapOperator
+	exposure float32
+	white    float32
+	sigma    float32
+	bias     float32
+}
+
+func (p *toneMapFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	dstBounds = image.Rect(0, 0, srcBounds.Dx(), srcBounds.Dy())
+	return
+}
+
+// maxLuminance returns the maximum luminance of the src image.
+func maxLuminance(src image.Image, options *Options) float32 {
+	srcb := src.Bounds()
+	pixGetter := newPixelGetter(src)
+
+	var mu sync.Mutex
+	var lmax float32
+	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
+		var m float32
+		for y := start; y < stop; y++ {
+			for x := srcb.Min.X; x < srcb.Max.X; x++ {
+				px := pixGetter.getPixel(x, y)
+				m = maxf32(m, luminance(px.r, px.g, px.b))
+			}
+		}
+		mu.Lock()
+		lmax = maxf32(lmax, m)
+		mu.Unlock()
+	})
+	return lmax
+}
+
+func (p *toneMapFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+
+	if srcb.Dx() <= 0 || srcb.Dy() <= 0 {
+		return
+	}
+
+	k := powf32(2, p.exposure)
+	white := p.white
+
+	var pixGetterLocal *pixelGetter
+	var lumScale float32
+	if p.op == tmReinhardLocal {
+		// Luminance is linear in the exposure, so the local adaptation
+		// luminance is computed before scaling and normalized by the maximum luminance
+		// to stay in the (0, 1) range of the temporary images.
+		lumScale = maxf32(maxLuminance(src, options), 1)
+		lum := createTempImage(srcb)
+		f := &colorFilter{
+			fn: func(px pixel) pixel {
+				l := luminance(px.r, px.g, px.b) / lumScale
+				return pixel{l, l, l, 1}
+			},
+		}
+		f.Draw(lum, src, options)
+		blurred := createTempImage(srcb)
+		GaussianBlur(p.sigma).Draw(blurred, lum, options)
+		pixGetterLocal = newPixelGetter(blurred)
+	}
+
+	var dragoScale, dragoExp float32
+	if p.op == tmDrago {
+		if white <= 0 {
+			white = maxLuminance(src, options) * k
+		}
+		if white <= 0 {
+			white = 1
+		}
+		dragoScale = 1 / float32(math.Log10(float64(white)+1))
+		dragoExp = logf32(p.bias) / logf32(0.5)
+	}
+
+	var hableWhite float32
+	if p.op == tmHable {
+		hableWhite = 1 / hable(white)
+	}
+
+	pixGetter := newPixelGetter(src)
+	pixSetter := newPixelSetter(dst)
+
+	parallelize(options.Parallelization, srcb.Min.Y, srcb.Max.Y, func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := srcb.Min.X; x < srcb.Max.X; x++ {
+				px := pixGetter.getPixel(x, y)
+				r, g, b := px.r*k, px.g*k, px.b*k
+
+				switch p.op {
+				case tmACES:
+					r, g, b = aces(r), aces(g), aces(b)
+
+				case tmHable:
+					r = hable(r) * hableWhite
+					g = hable(g) * hableWhite
+					b = hable(b) * hableWhite
+
+				default:
+					l := luminance(r, g, b)
+					if l <= 0 {
+						r, g, b = 0, 0, 0
+						break
+					}
+					var ld float32
+					switch p.op {
+					case tmReinhardLocal:
+						la := pixGetterLocal.getPixel(x, y).r * lumScale * k
+						ld = l * (1 + l/(white*white)) / (1 + la)
+					case tmDrago:
+						ld = dragoScale * logf32(l+1) / logf32(2+8*powf32(l/white, dragoExp))
+					default:
+						ld = l * (1 + l/(white*white)) / (1 + l)
+					}
+					s := ld / l
+					r, g, b = r*s, g*s, b*s
+				}
+
+				pixSetter.setPixel(dstb.Min.X+x-srcb.Min.X, dstb.Min.Y+y-srcb.Min.Y, pixel{r, g, b, px.a})
+			}
+		}
+	})
+}
+
+// ToneMapReinhard creates a filter that maps high dynamic range colors to the displayable range
+// using the global Reinhard operator. The image colors are expected to be in linear RGB.
+// The exposure parameter is the exposure adjustment in stops applied before the mapping, 0 keeps the original exposure.
+// The white parameter is the smallest luminance that is mapped to pure white, it must be positive.
+//
+// The values above 1 are only read from a FloatImage src, the images passed between the filters
+// are clamped to [0, 1], so the tone mapping filter must be the first one.
+// For a regular image the exposure adjustment is the only source of the values above 1.
+//
+// Example:
+//
+//	// hdr is a *gift.FloatImage with linear RGB values.
+//	g := gift.New(
+//		gift.ToneMapReinhard(0, 16),
+//		gift.ColorspaceLinearToSRGB(),
+//	)
+//	dst := image.NewRGBA(g.Bounds(hdr.Bounds()))
+//	g.Draw(dst, hdr)
+//
+func ToneMapReinhard(exposure, white float32) Filter {
+	return &toneMapFilter{
+		op:       tmReinhard,
+		exposure: exposure,
+		white:    maxf32(white, 1.0e-5),
+	}
+}
+
+// ToneMapReinhardLocal creates a filter that maps high dynamic range colors to the displayable range
+// using the local Reinhard operator. Each pixel is adapted to the luminance of its neighborhood
+// which preserves more local contrast than the global operator.
+// The exposure and white parameters and the input range are the same as in ToneMapReinhard.
+// The sigma parameter is the gaussian blur sigma used to compute the local adaptation luminance, it must be positive.
+func ToneMapReinhardLocal(exposure, white, sigma float32) Filter {
+	return &toneMapFilter{
+		op:       tmReinhardLocal,
+		exposure: exposure,
+		white:    maxf32(white, 1.0e-5),
+		sigma:    sigma,
+	}
+}
+
+// ToneMapACES creates a filter that maps high dynamic range colors to the displayable range
+// using the ACES filmic curve approximation. The image colors are expected to be in linear RGB.
+// The exposure parameter is the exposure adjustment in stops applied before the mapping.
+// See ToneMapReinhard for passing the values above 1.
+func ToneMapACES(exposure float32) Filter {
+	return &toneMapFilter{
+		op:       tmACES,
+		exposure: exposure,
+	}
+}
+
+// ToneMapHable creates a filter that maps high dynamic range colors to the displayable range
+// using the Hable (Uncharted 2) filmic curve. The image colors are expected to be in linear RGB,
+// the values above 1 are read from a FloatImage src (see ToneMapReinhard).
+// The exposure parameter is the exposure adjustment in stops applied before the mapping.
+// The white parameter is the linear white point that is mapped to 1, typically 11.2.
+func ToneMapHable(exposure, white float32) Filter {
+	return &toneMapFilter{
+		op:       tmHable,
+		exposure: exposure,
+		white:    maxf32(white, 1.0e-5),
+	}
+}
+
+// ToneMapDrago creates a filter that maps high dynamic range colors to the displayable range
+// using the Drago adaptive logarithmic operator. The image colors are expected to be in linear RGB.
+// The exposure parameter is the exposure adjustment in stops applied before the mapping.
+// The white parameter is the maximum scene luminance after the exposure adjustment.
+// If it is 0 or negative, the maximum luminance of the image is used.
+// Like with the other tone mapping filters, the values above 1 are read from a FloatImage src (see ToneMapReinhard).
+// The bias parameter controls the contrast of the result, it must be in range (0, 1), typically 0.85.
+func ToneMapDrago(exposure, white, bias float32) Filter {
+	return &toneMapFilter{
+		op:       tmDrago,
+		exposure: exposure,
+		white:    white,
+		bias:     minf32(maxf32(bias, 0.01), 0.99),
+	}
+}
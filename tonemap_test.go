@@ -0,0 +1,193 @@
+package gift
+
+import (
+	"image"
+	"testing"
+)
+
+func TestToneMap(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"reinhard (0, 1)",
+			ToneMapReinhard(0, 1),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x00, 0x40, 0x80,
+				0xc0, 0xe0, 0xff,
+			},
+			[]uint8{
+				0x00, 0x40, 0x80,
+				0xc0, 0xe0, 0xff,
+			},
+		},
+		{
+			"reinhard (0, 100)",
+			ToneMapReinhard(0, 100),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x00, 0x40, 0x80,
+				0xc0, 0xe0, 0xff,
+			},
+			[]uint8{
+				0x00, 0x33, 0x55,
+				0x6e, 0x77, 0x80,
+			},
+		},
+		{
+			"reinhard (1, 2)",
+			ToneMapReinhard(1, 2),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x00, 0x40, 0x80,
+				0xc0, 0xe0, 0xff,
+			},
+			[]uint8{
+				0x00, 0x60, 0xa0,
+				0xd3, 0xea, 0xff,
+			},
+		},
+		{
+			"reinhard local (0, 100, 1)",
+			ToneMapReinhardLocal(0, 100, 1),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x80, 0x80, 0x80,
+				0x80, 0x80, 0x80,
+			},
+			[]uint8{
+				0x55, 0x55, 0x55,
+				0x55, 0x55, 0x55,
+			},
+		},
+		{
+			"hable (0, 1)",
+			ToneMapHable(0, 1),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x00, 0x40, 0x80,
+				0xc0, 0xe0, 0xff,
+			},
+			[]uint8{
+				0x00, 0x4d, 0x91,
+				0xcc, 0xe7, 0xff,
+			},
+		},
+		{
+			"drago (0, 0, 0.85)",
+			ToneMapDrago(0, 0, 0.85),
+			image.Rect(-1, -1, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{
+				0x00, 0x08, 0x10,
+				0x20, 0x40, 0x80,
+			},
+			[]uint8{
+				0x00, 0x18, 0x2d,
+				0x53, 0x95, 0xff,
+			},
+		},
+		{
+			"reinhard 0x0",
+			ToneMapReinhard(0, 1),
+			image.Rect(-1, -1, -1, -1),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestToneMapACES(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 256, 1))
+	for i := 0; i <= 255; i++ {
+		src.Pix[i] = uint8(i)
+	}
+	for _, exposure := range []float32{-2, 0, 2, 4} {
+		f := ToneMapACES(exposure)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if dst.Pix[0] != 0 {
+			t.Errorf("ToneMapACES(%v): expected black to stay black, got %d", exposure, dst.Pix[0])
+		}
+		for i := 1; i <= 255; i++ {
+			if dst.Pix[i] < dst.Pix[i-1] {
+				t.Errorf("ToneMapACES(%v): curve is not monotonic at %d", exposure, i)
+			}
+		}
+	}
+}
+
+func TestToneMapFloatImage(t *testing.T) {
+	newHDR := func(lums ...float32) *FloatImage {
+		img := NewFloatImage(image.Rect(-1, 2, len(lums)-1, 3))
+		for i, l := range lums {
+			img.SetFloat(i-1, 2, l, l, l, 1)
+		}
+		return img
+	}
+	draw := func(f Filter, src image.Image) []float32 {
+		dst := image.NewGray16(f.Bounds(src.Bounds()))
+		New(f).Draw(dst, src)
+		res := make([]float32, 0, dst.Bounds().Dx())
+		for x := 0; x < dst.Bounds().Dx(); x++ {
+			res = append(res, float32(dst.Gray16At(x, 0).Y)/0xffff)
+		}
+		return res
+	}
+	near := func(got []float32, want ...float32) bool {
+		for i := range want {
+			if absf32(got[i]-want[i]) > 0.002 {
+				return false
+			}
+		}
+		return true
+	}
+
+	// The values above 1 reach the operator: 4 is mapped to white and 1 to a mid-tone.
+	if got := draw(ToneMapReinhard(0, 4), newHDR(0, 1, 4, 16)); !near(got, 0, 0.53125, 1, 1) {
+		t.Errorf("reinhard: unexpected result %v", got)
+	}
+
+	// The maximum luminance of the image is mapped to white.
+	got := draw(ToneMapDrago(0, 0, 0.85), newHDR(1, 4, 16))
+	if got[2] != 1 || got[1] >= 1 || got[0] >= got[1] {
+		t.Errorf("drago: unexpected result %v", got)
+	}
+
+	// The local adaptation luminance isn't clamped either.
+	if got := draw(ToneMapReinhardLocal(0, 100, 1), newHDR(8, 8, 8)); !near(got, 0.8896, 0.8896, 0.8896) {
+		t.Errorf("reinhard local: unexpected result %v", got)
+	}
+
+	// A regular image is clamped to [0, 1] and the highlights are lost.
+	ldr := image.NewNRGBA64(image.Rect(0, 0, 2, 1))
+	hdr := newHDR(1, 4)
+	for x := 0; x < 2; x++ {
+		ldr.Set(x, 0, hdr.At(x-1, 2))
+	}
+	if got := draw(ToneMapReinhard(0, 4), ldr); !near(got, 0.53125, 0.53125) {
+		t.Errorf("clamped: unexpected result %v", got)
+	}
+}
@@ -16,6 +16,7 @@ const (
 	ttFlipVertical
 	ttTranspose
 	ttTransverse
+	ttNone
 )
 
 type transformFilter struct {
@@ -68,6 +69,9 @@ func (p *transformFilter) Draw(dst draw.Image, src image.Image, options *Options
 				case ttTransverse:
 					dstx = dstb.Min.Y + srcb.Max.Y - srcy - 1
 					dsty = dstb.Min.X + srcb.Max.X - srcx - 1
+				default:
+					dstx = dstb.Min.X + srcx - srcb.Min.X
+					dsty = dstb.Min.Y + srcy - srcb.Min.Y
 				}
 				pixSetter.setPixel(dstx, dsty, pixGetter.getPixel(srcx, srcy))
 			}
@@ -124,6 +128,57 @@ func Transverse() Filter {
 	}
 }
 
+// Orientation creates a filter that transforms an image stored with the given EXIF orientation tag value
+// so that it is displayed upright. The values 1 to 8 are supported, any other value leaves the image unchanged.
+// See ReadOrientation for reading the tag value.
+//
+// Example:
+//
+//	f, err := os.Open("photo.jpg")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	orientation, err := gift.ReadOrientation(f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if _, err := f.Seek(0, io.SeekStart); err != nil {
+//		log.Fatal(err)
+//	}
+//	src, err := jpeg.Decode(f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	g := gift.New(
+//		gift.Orientation(orientation),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Orientation(orientation int) Filter {
+	tt := ttNone
+	switch orientation {
+	case 2:
+		tt = ttFlipHorizontal
+	case 3:
+		tt = ttRotate180
+	case 4:
+		tt = ttFlipVertical
+	case 5:
+		tt = ttTranspose
+	case 6:
+		tt = ttRotate270
+	case 7:
+		tt = ttTransverse
+	case 8:
+		tt = ttRotate90
+	}
+	return &transformFilter{
+		tt: tt,
+	}
+}
+
 // Interpolation is an interpolation algorithm used for image transformation.
 type Interpolation int
 
@@ -134,8 +189,22 @@ const (
 	LinearInterpolation
 	// CubicInterpolation is a bicubic interpolation algorithm.
 	CubicInterpolation
+	// LanczosInterpolation is a Lanczos interpolation algorithm with a 6x6 neighborhood.
+	// It keeps more detail than the bicubic interpolation, e.g. when deskewing text scans.
+	LanczosInterpolation
+	// AreaInterpolation is a supersampling interpolation algorithm. Every destination pixel is the average
+	// of several bilinear samples spread over the area it covers in the source image, so it avoids aliasing
+	// when a transformation shrinks the image.
+	AreaInterpolation
 )
 
+// interpolator defines how the colors of the src image are sampled by the warp function.
+type interpolator struct {
+	interpolation Interpolation
+	// kernel, if not nil, is used instead of the interpolation algorithm.
+	kernel Resampling
+}
+
 func rotatePoint(x, y, asin, acos float32) (float32, float32) {
 	newx := x*acos - y*asin
 	newy := x*asin + y*acos
@@ -173,9 +242,9 @@ func calcRotatedSize(w, h int, angle float32) (int, int) {
 }
 
 type rotateFilter struct {
-	angle         float32
-	bgcolor       color.Color
-	interpolation Interpolation
+	angle   float32
+	bgcolor color.Color
+	interp  interpolator
 }
 
 func (p *rotateFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
@@ -190,7 +259,6 @@ func (p *rotateFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 	}
 
 	srcb := src.Bounds()
-	dstb := dst.Bounds()
 
 	w, h := calcRotatedSize(srcb.Dx(), srcb.Dy(), p.angle)
 	if w <= 0 || h <= 0 {
@@ -205,51 +273,174 @@ func (p *rotateFilter) Draw(dst draw.Image, src image.Image, options *Options) {
 	bgpx := pixelFromColor(p.bgcolor)
 	asin, acos := sincosf32(p.angle)
 
-	pixGetter := newPixelGetter(src)
+	warp(dst, src, w, h, func(x, y float32) (float32, float32, bool) {
+		xf, yf := rotatePoint(x-dstxoff, y-dstyoff, asin, acos)
+		return float32(srcb.Min.X) + xf + srcxoff, float32(srcb.Min.Y) + yf + srcyoff, true
+	}, p.interp, bgpx, options)
+}
+
+// warp draws a w x h image to the dst image using inverse mapping. The mapping function
+// receives the coordinates of a dst pixel relative to the dst image Min point and returns
+// the corresponding coordinates in the src image or false if the pixel must be set to the background.
+// Pixel centers are at integer coordinates.
+func warp(dst draw.Image, src image.Image, w, h int, mapping func(x, y float32) (float32, float32, bool), interp interpolator, bgpx pixel, options *Options) {
+	dstb := dst.Bounds()
+
+	// The area beyond the src image is filled with the background color unless the border mode extends the image.
+	border := options.BorderMode
+	if border == ClampBorderMode {
+		border = ConstantBorderMode
+	}
+	pixGetter := newBorderPixelGetter(src, border, bgpx)
 	pixSetter := newPixelSetter(dst)
 
+	if interp.kernel == nil && interp.interpolation == AreaInterpolation {
+		parallelize(options.Parallelization, 0, h, func(start, stop int) {
+			for y := start; y < stop; y++ {
+				for x := 0; x < w; x++ {
+					px := supersample(float32(x), float32(y), mapping, pixGetter)
+					pixSetter.setPixel(dstb.Min.X+x, dstb.Min.Y+y, px)
+				}
+			}
+		})
+		return
+	}
+
 	parallelize(options.Parallelization, 0, h, func(start, stop int) {
 		for y := start; y < stop; y++ {
 			for x := 0; x < w; x++ {
-
-				xf, yf := rotatePoint(float32(x)-dstxoff, float32(y)-dstyoff, asin, acos)
-				xf, yf = float32(srcb.Min.X)+xf+srcxoff, float32(srcb.Min.Y)+yf+srcyoff
-				var px pixel
-
-				switch p.interpolation {
-				case CubicInterpolation:
-					px = interpolateCubic(xf, yf, srcb, pixGetter, bgpx)
-				case LinearInterpolation:
-					px = interpolateLinear(xf, yf, srcb, pixGetter, bgpx)
-				default:
-					px = interpolateNearest(xf, yf, srcb, pixGetter, bgpx)
+				px := bgpx
+				if xf, yf, ok := mapping(float32(x), float32(y)); ok {
+					px = interpolate(interp, xf, yf, pixGetter)
 				}
-
 				pixSetter.setPixel(dstb.Min.X+x, dstb.Min.Y+y, px)
 			}
 		}
 	})
 }
 
-func interpolateCubic(xf, yf float32, bounds image.Rectangle, pixGetter *pixelGetter, bgpx pixel) pixel {
+// interpolate calculates the color at the given point of the src image using the specified interpolator.
+func interpolate(interp interpolator, xf, yf float32, pixGetter *borderPixelGetter) pixel {
+	if interp.kernel != nil {
+		return interpolateKernel(interp.kernel, xf, yf, pixGetter)
+	}
+	switch interp.interpolation {
+	case LanczosInterpolation:
+		return interpolateKernel(LanczosResampling, xf, yf, pixGetter)
+	case CubicInterpolation:
+		return interpolateCubic(xf, yf, pixGetter)
+	case LinearInterpolation, AreaInterpolation:
+		return interpolateLinear(xf, yf, pixGetter)
+	default:
+		return interpolateNearest(xf, yf, pixGetter)
+	}
+}
+
+// supersample calculates the color of the dst pixel (x, y) as the average of n x n bilinear samples
+// of the area it covers in the src image. The number of samples depends on the local scale of the mapping.
+func supersample(x, y float32, mapping func(x, y float32) (float32, float32, bool), pixGetter *borderPixelGetter) pixel {
+	n := 1
+	if x0, y0, ok := mapping(x, y); ok {
+		scale := float32(1)
+		if x1, y1, ok := mapping(x+1, y); ok {
+			scale = maxf32(scale, sqrtf32((x1-x0)*(x1-x0)+(y1-y0)*(y1-y0)))
+		}
+		if x1, y1, ok := mapping(x, y+1); ok {
+			scale = maxf32(scale, sqrtf32((x1-x0)*(x1-x0)+(y1-y0)*(y1-y0)))
+		}
+		n = minint(int(ceilf32(scale-0.01)), 8)
+	}
+
+	var px pixel
+	step := 1 / float32(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			sx := x + (float32(j)+0.5)*step - 0.5
+			sy := y + (float32(i)+0.5)*step - 0.5
+			spx := pixGetter.px
+			if xf, yf, ok := mapping(sx, sy); ok {
+				spx = interpolateLinear(xf, yf, pixGetter)
+			}
+			px.r += spx.r * spx.a
+			px.g += spx.g * spx.a
+			px.b += spx.b * spx.a
+			px.a += spx.a
+		}
+	}
+
+	if px.a != 0 {
+		px.r /= px.a
+		px.g /= px.a
+		px.b /= px.a
+	}
+	px.a /= float32(n * n)
+
+	return px
+}
+
+func interpolateKernel(kernel Resampling, xf, yf float32, pixGetter *borderPixelGetter) pixel {
+	support := kernel.Support()
+	if support <= 0 {
+		return interpolateNearest(xf, yf, pixGetter)
+	}
+
+	x0, y0 := int(floorf32(xf)), int(floorf32(yf))
+	if !pixGetter.covers(x0, y0) {
+		return pixGetter.px
+	}
+
+	xmin, xmax := int(floorf32(xf-support))+1, int(floorf32(xf+support))
+	ymin, ymax := int(floorf32(yf-support))+1, int(floorf32(yf+support))
+
+	var px pixel
+	var sum float32
+	for y := ymin; y <= ymax; y++ {
+		wy := kernel.Kernel(float32(y) - yf)
+		if wy == 0 {
+			continue
+		}
+		for x := xmin; x <= xmax; x++ {
+			w := wy * kernel.Kernel(float32(x)-xf)
+			if w == 0 {
+				continue
+			}
+			p := pixGetter.getPixel(x, y)
+			wa := p.a * w
+			px.r += p.r * wa
+			px.g += p.g * wa
+			px.b += p.b * wa
+			px.a += wa
+			sum += w
+		}
+	}
+
+	if sum == 0 {
+		return interpolateNearest(xf, yf, pixGetter)
+	}
+	if px.a != 0 {
+		px.r /= px.a
+		px.g /= px.a
+		px.b /= px.a
+	}
+	px.a /= sum
+
+	return px
+}
+
+func interpolateCubic(xf, yf float32, pixGetter *borderPixelGetter) pixel {
 	var pxs [16]pixel
 	var cfs [16]float32
 	var px pixel
 
 	x0, y0 := int(floorf32(xf)), int(floorf32(yf))
-	if !image.Pt(x0, y0).In(image.Rect(bounds.Min.X-1, bounds.Min.Y-1, bounds.Max.X, bounds.Max.Y)) {
-		return bgpx
+	if !pixGetter.covers(x0, y0) {
+		return pixGetter.px
 	}
 	xq, yq := xf-float32(x0), yf-float32(y0)
 
 	for i := 0; i < 4; i++ {
 		for j := 0; j < 4; j++ {
-			pt := image.Pt(x0+j-1, y0+i-1)
-			if pt.In(bounds) {
-				pxs[i*4+j] = pixGetter.getPixel(pt.X, pt.Y)
-			} else {
-				pxs[i*4+j] = bgpx
-			}
+			pxs[i*4+j] = pixGetter.getPixel(x0+j-1, y0+i-1)
 		}
 	}
 
@@ -293,25 +484,20 @@ func interpolateCubic(xf, yf float32, bounds image.Rectangle, pixGetter *pixelGe
 	return px
 }
 
-func interpolateLinear(xf, yf float32, bounds image.Rectangle, pixGetter *pixelGetter, bgpx pixel) pixel {
+func interpolateLinear(xf, yf float32, pixGetter *borderPixelGetter) pixel {
 	var pxs [4]pixel
 	var cfs [4]float32
 	var px pixel
 
 	x0, y0 := int(floorf32(xf)), int(floorf32(yf))
-	if !image.Pt(x0, y0).In(image.Rect(bounds.Min.X-1, bounds.Min.Y-1, bounds.Max.X, bounds.Max.Y)) {
-		return bgpx
+	if !pixGetter.covers(x0, y0) {
+		return pixGetter.px
 	}
 	xq, yq := xf-float32(x0), yf-float32(y0)
 
 	for i := 0; i < 2; i++ {
 		for j := 0; j < 2; j++ {
-			pt := image.Pt(x0+j, y0+i)
-			if pt.In(bounds) {
-				pxs[i*2+j] = pixGetter.getPixel(pt.X, pt.Y)
-			} else {
-				pxs[i*2+j] = bgpx
-			}
+			pxs[i*2+j] = pixGetter.getPixel(x0+j, y0+i)
 		}
 	}
 
@@ -337,19 +523,16 @@ func interpolateLinear(xf, yf float32, bounds image.Rectangle, pixGetter *pixelG
 	return px
 }
 
-func interpolateNearest(xf, yf float32, bounds image.Rectangle, pixGetter *pixelGetter, bgpx pixel) pixel {
-	x0, y0 := int(floorf32(xf+0.5)), int(floorf32(yf+0.5))
-	if image.Pt(x0, y0).In(bounds) {
-		return pixGetter.getPixel(x0, y0)
-	}
-	return bgpx
+func interpolateNearest(xf, yf float32, pixGetter *borderPixelGetter) pixel {
+	return pixGetter.getPixel(int(floorf32(xf+0.5)), int(floorf32(yf+0.5)))
 }
 
 // Rotate creates a filter that rotates an image by the given angle counter-clockwise.
 // The angle parameter is the rotation angle in degrees.
 // The backgroundColor parameter specifies the color of the uncovered zone after the rotation.
 // The interpolation parameter specifies the interpolation method.
-// Supported interpolation methods: NearestNeighborInterpolation, LinearInterpolation, CubicInterpolation.
+// Supported interpolation methods: NearestNeighborInterpolation, LinearInterpolation, CubicInterpolation,
+// LanczosInterpolation, AreaInterpolation.
 //
 // Example:
 //
@@ -361,9 +544,30 @@ func interpolateNearest(xf, yf float32, bounds image.Rectangle, pixGetter *pixel
 //
 func Rotate(angle float32, backgroundColor color.Color, interpolation Interpolation) Filter {
 	return &rotateFilter{
-		angle:         angle,
-		bgcolor:       backgroundColor,
-		interpolation: interpolation,
+		angle:   angle,
+		bgcolor: backgroundColor,
+		interp:  interpolator{interpolation: interpolation},
+	}
+}
+
+// RotateKernel creates a filter that rotates an image by the given angle counter-clockwise
+// using the given resampling filter as the interpolation kernel (e.g. LanczosResampling, MitchellResampling
+// or a custom one created with NewResampling). It is the same as Rotate otherwise.
+//
+// Example:
+//
+//	// Deskew a text scan keeping the glyph edges sharp.
+//	g := gift.New(
+//		gift.RotateKernel(1.5, color.White, gift.Lanczos4Resampling),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func RotateKernel(angle float32, backgroundColor color.Color, resampling Resampling) Filter {
+	return &rotateFilter{
+		angle:   angle,
+		bgcolor: backgroundColor,
+		interp:  interpolator{kernel: resampling},
 	}
 }
 
@@ -496,3 +700,127 @@ func CropToSize(width, height int, anchor Anchor) Filter {
 		anchor: anchor,
 	}
 }
+
+type padFilter struct {
+	top, right, bottom, left int
+	mode                     BorderMode
+	color                    color.Color
+}
+
+func (p *padFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	if srcBounds.Dx() <= 0 || srcBounds.Dy() <= 0 {
+		return image.Rect(0, 0, 0, 0)
+	}
+	w := srcBounds.Dx() + maxint(p.left, 0) + maxint(p.right, 0)
+	h := srcBounds.Dy() + maxint(p.top, 0) + maxint(p.bottom, 0)
+	dstBounds = image.Rect(0, 0, w, h)
+	return
+}
+
+func (p *padFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	if options == nil {
+		options = &defaultOptions
+	}
+
+	srcb := src.Bounds()
+	dstb := dst.Bounds()
+	b := p.Bounds(srcb)
+	if b.Empty() {
+		return
+	}
+
+	left, top := maxint(p.left, 0), maxint(p.top, 0)
+	pixGetter := newBorderPixelGetter(src, p.mode, borderPixel(p.color))
+	pixSetter := newPixelSetter(dst)
+
+	parallelize(options.Parallelization, 0, b.Dy(), func(start, stop int) {
+		for y := start; y < stop; y++ {
+			for x := 0; x < b.Dx(); x++ {
+				px := pixGetter.getPixel(srcb.Min.X+x-left, srcb.Min.Y+y-top)
+				pixSetter.setPixel(dstb.Min.X+x, dstb.Min.Y+y, px)
+			}
+		}
+	})
+}
+
+// Pad creates a filter that adds the given number of pixels to each side of an image.
+// The mode parameter specifies how the new area is filled: ClampBorderMode repeats the edge pixels,
+// ReflectBorderMode and Reflect101BorderMode mirror the image, WrapBorderMode tiles it
+// and ConstantBorderMode fills the area with the given color (transparent if it's nil).
+// Negative values are treated as 0.
+//
+// Example:
+//
+//	// Add a 10px mirrored margin around the image.
+//	g := gift.New(
+//		gift.Pad(10, 10, 10, 10, gift.ReflectBorderMode, nil),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func Pad(top, right, bottom, left int, mode BorderMode, c color.Color) Filter {
+	return &padFilter{
+		top:    top,
+		right:  right,
+		bottom: bottom,
+		left:   left,
+		mode:   mode,
+		color:  c,
+	}
+}
+
+type extendCanvasFilter struct {
+	w, h   int
+	anchor Anchor
+	color  color.Color
+}
+
+// pad returns the padding filter that extends the src image to the canvas size.
+func (p *extendCanvasFilter) pad(srcBounds image.Rectangle) *padFilter {
+	w, h := srcBounds.Dx(), srcBounds.Dy()
+	canvas := image.Rect(0, 0, maxint(p.w, w), maxint(p.h, h))
+	pt := anchorPt(canvas, w, h, p.anchor)
+	return &padFilter{
+		top:    pt.Y,
+		right:  canvas.Dx() - w - pt.X,
+		bottom: canvas.Dy() - h - pt.Y,
+		left:   pt.X,
+		mode:   ConstantBorderMode,
+		color:  p.color,
+	}
+}
+
+func (p *extendCanvasFilter) Bounds(srcBounds image.Rectangle) (dstBounds image.Rectangle) {
+	return p.pad(srcBounds).Bounds(srcBounds)
+}
+
+func (p *extendCanvasFilter) Draw(dst draw.Image, src image.Image, options *Options) {
+	p.pad(src.Bounds()).Draw(dst, src, options)
+}
+
+// ExtendCanvas creates a filter that extends the canvas of an image to the specified size
+// placing the image according to the anchor point and filling the new area with the given color
+// (transparent if it's nil). The dimensions smaller than the image size are left unchanged.
+//
+// Example:
+//
+//	// Make the image square, keep it at the bottom center.
+//	b := src.Bounds()
+//	size := b.Dx()
+//	if b.Dy() > size {
+//		size = b.Dy()
+//	}
+//	g := gift.New(
+//		gift.ExtendCanvas(size, size, gift.BottomAnchor, color.White),
+//	)
+//	dst := image.NewRGBA(g.Bounds(src.Bounds()))
+//	g.Draw(dst, src)
+//
+func ExtendCanvas(width, height int, anchor Anchor, c color.Color) Filter {
+	return &extendCanvasFilter{
+		w:      width,
+		h:      height,
+		anchor: anchor,
+		color:  c,
+	}
+}
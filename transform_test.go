@@ -618,6 +618,61 @@ func TestRotate(t *testing.T) {
 				0x00, 0x00, 0x00, 0x23, 0x23, 0x00, 0x00, 0x00,
 			},
 		},
+		{
+			"rotate 3x3 -90 white lanczos",
+			-90, color.White, LanczosInterpolation,
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 3, 3),
+			[]uint8{
+				0x10, 0x20, 0x30,
+				0x40, 0x50, 0x60,
+				0x70, 0x80, 0x90,
+			},
+			[]uint8{
+				0x70, 0x40, 0x10,
+				0x80, 0x50, 0x20,
+				0x90, 0x60, 0x30,
+			},
+		},
+		{
+			"rotate 3x3 -90 white area",
+			-90, color.White, AreaInterpolation,
+			image.Rect(-1, -1, 2, 2),
+			image.Rect(0, 0, 3, 3),
+			[]uint8{
+				0x10, 0x20, 0x30,
+				0x40, 0x50, 0x60,
+				0x70, 0x80, 0x90,
+			},
+			[]uint8{
+				0x70, 0x40, 0x10,
+				0x80, 0x50, 0x20,
+				0x90, 0x60, 0x30,
+			},
+		},
+		{
+			"rotate 5x5 45 black lanczos",
+			45, color.Black, LanczosInterpolation,
+			image.Rect(-1, -1, 4, 4),
+			image.Rect(0, 0, 8, 8),
+			[]uint8{
+				0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			[]uint8{
+				0x00, 0x00, 0x00, 0x1f, 0x1f, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x21, 0xff, 0xff, 0x21, 0x00, 0x00,
+				0x00, 0x21, 0xe8, 0xff, 0xff, 0xe8, 0x21, 0x00,
+				0x1f, 0xff, 0xff, 0xf7, 0xf7, 0xff, 0xff, 0x1f,
+				0x1f, 0xff, 0xff, 0xf7, 0xf7, 0xff, 0xff, 0x1f,
+				0x00, 0x21, 0xe8, 0xff, 0xff, 0xe8, 0x21, 0x00,
+				0x00, 0x00, 0x21, 0xff, 0xff, 0x21, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x1f, 0x1f, 0x00, 0x00, 0x00,
+			},
+		},
 	}
 
 	for _, d := range testData {
@@ -634,3 +689,224 @@ func TestRotate(t *testing.T) {
 	}
 
 }
+
+func TestRotateKernel(t *testing.T) {
+	src := image.NewGray(image.Rect(-1, -1, 2, 2))
+	src.Pix = []uint8{
+		0x10, 0x20, 0x30,
+		0x40, 0x50, 0x60,
+		0x70, 0x80, 0x90,
+	}
+	want := []uint8{
+		0x30, 0x60, 0x90,
+		0x20, 0x50, 0x80,
+		0x10, 0x40, 0x70,
+	}
+
+	for _, r := range []Resampling{NearestNeighborResampling, BoxResampling, LinearResampling, CubicResampling, LanczosResampling, Lanczos4Resampling} {
+		f := RotateKernel(90, color.White, r)
+		dst := image.NewGray(f.Bounds(src.Bounds()))
+		f.Draw(dst, src, nil)
+		if !checkBoundsAndPix(dst.Bounds(), image.Rect(0, 0, 3, 3), dst.Pix, want) {
+			t.Errorf("test [%s] failed: %#v, %#v", r, dst.Bounds(), dst.Pix)
+		}
+	}
+
+	// The linear kernel is the same as the linear interpolation.
+	src = image.NewGray(image.Rect(0, 0, 5, 5))
+	for i := range src.Pix {
+		src.Pix[i] = uint8(i * 10)
+	}
+	f0 := RotateKernel(30, color.Black, LinearResampling)
+	f1 := Rotate(30, color.Black, LinearInterpolation)
+	dst0 := image.NewGray(f0.Bounds(src.Bounds()))
+	dst1 := image.NewGray(f1.Bounds(src.Bounds()))
+	f0.Draw(dst0, src, nil)
+	f1.Draw(dst1, src, nil)
+	if !checkBoundsAndPix(dst0.Bounds(), dst1.Bounds(), dst0.Pix, dst1.Pix) {
+		t.Errorf("linear kernel: expected %#v got %#v", dst1.Pix, dst0.Pix)
+	}
+}
+
+func TestPad(t *testing.T) {
+	testData := []struct {
+		desc           string
+		f              Filter
+		srcb, dstb     image.Rectangle
+		srcPix, dstPix []uint8
+	}{
+		{
+			"pad constant",
+			Pad(1, 2, 0, 1, ConstantBorderMode, color.White),
+			image.Rect(-1, -1, 1, 1),
+			image.Rect(0, 0, 5, 3),
+			[]uint8{
+				0x01, 0x02,
+				0x03, 0x04,
+			},
+			[]uint8{
+				0xff, 0xff, 0xff, 0xff, 0xff,
+				0xff, 0x01, 0x02, 0xff, 0xff,
+				0xff, 0x03, 0x04, 0xff, 0xff,
+			},
+		},
+		{
+			"pad transparent",
+			Pad(0, 1, 1, 0, ConstantBorderMode, nil),
+			image.Rect(0, 0, 2, 2),
+			image.Rect(0, 0, 3, 3),
+			[]uint8{
+				0x01, 0x02,
+				0x03, 0x04,
+			},
+			[]uint8{
+				0x01, 0x02, 0x00,
+				0x03, 0x04, 0x00,
+				0x00, 0x00, 0x00,
+			},
+		},
+		{
+			"pad clamp",
+			Pad(1, 1, 1, 1, ClampBorderMode, nil),
+			image.Rect(0, 0, 2, 2),
+			image.Rect(0, 0, 4, 4),
+			[]uint8{
+				0x01, 0x02,
+				0x03, 0x04,
+			},
+			[]uint8{
+				0x01, 0x01, 0x02, 0x02,
+				0x01, 0x01, 0x02, 0x02,
+				0x03, 0x03, 0x04, 0x04,
+				0x03, 0x03, 0x04, 0x04,
+			},
+		},
+		{
+			"pad reflect101",
+			Pad(0, 2, 0, 2, Reflect101BorderMode, nil),
+			image.Rect(0, 0, 3, 1),
+			image.Rect(0, 0, 7, 1),
+			[]uint8{0x01, 0x02, 0x03},
+			[]uint8{0x03, 0x02, 0x01, 0x02, 0x03, 0x02, 0x01},
+		},
+		{
+			"pad wrap",
+			Pad(1, 0, 0, 2, WrapBorderMode, nil),
+			image.Rect(0, 0, 3, 2),
+			image.Rect(0, 0, 5, 3),
+			[]uint8{
+				0x01, 0x02, 0x03,
+				0x04, 0x05, 0x06,
+			},
+			[]uint8{
+				0x05, 0x06, 0x04, 0x05, 0x06,
+				0x02, 0x03, 0x01, 0x02, 0x03,
+				0x05, 0x06, 0x04, 0x05, 0x06,
+			},
+		},
+		{
+			"pad negative",
+			Pad(-1, -1, 1, -1, ReflectBorderMode, nil),
+			image.Rect(0, 0, 2, 1),
+			image.Rect(0, 0, 2, 2),
+			[]uint8{0x01, 0x02},
+			[]uint8{
+				0x01, 0x02,
+				0x01, 0x02,
+			},
+		},
+		{
+			"pad 0x0",
+			Pad(1, 1, 1, 1, ConstantBorderMode, color.White),
+			image.Rect(0, 0, 0, 0),
+			image.Rect(0, 0, 0, 0),
+			[]uint8{},
+			[]uint8{},
+		},
+		{
+			"extend canvas center",
+			ExtendCanvas(4, 3, CenterAnchor, color.White),
+			image.Rect(-1, -1, 1, 0),
+			image.Rect(0, 0, 4, 3),
+			[]uint8{0x01, 0x02},
+			[]uint8{
+				0xff, 0xff, 0xff, 0xff,
+				0xff, 0x01, 0x02, 0xff,
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			"extend canvas bottom right",
+			ExtendCanvas(3, 2, BottomRightAnchor, color.White),
+			image.Rect(0, 0, 2, 1),
+			image.Rect(0, 0, 3, 2),
+			[]uint8{0x01, 0x02},
+			[]uint8{
+				0xff, 0xff, 0xff,
+				0xff, 0x01, 0x02,
+			},
+		},
+		{
+			"extend canvas smaller",
+			ExtendCanvas(1, 3, TopAnchor, color.White),
+			image.Rect(0, 0, 2, 1),
+			image.Rect(0, 0, 2, 3),
+			[]uint8{0x01, 0x02},
+			[]uint8{
+				0x01, 0x02,
+				0xff, 0xff,
+				0xff, 0xff,
+			},
+		},
+	}
+
+	for _, d := range testData {
+		src := image.NewGray(d.srcb)
+		src.Pix = d.srcPix
+
+		dst := image.NewGray(d.f.Bounds(src.Bounds()))
+		d.f.Draw(dst, src, nil)
+
+		if !checkBoundsAndPix(dst.Bounds(), d.dstb, dst.Pix, d.dstPix) {
+			t.Errorf("test [%s] failed: %#v, %#v", d.desc, dst.Bounds(), dst.Pix)
+		}
+	}
+}
+
+func TestOrientation(t *testing.T) {
+	img0 := image.NewGray(image.Rect(-1, -1, 2, 1))
+	img0.Pix = []uint8{
+		1, 2, 3,
+		4, 5, 6,
+	}
+
+	testData := []struct {
+		orientation int
+		size        image.Point
+		pix         []uint8
+	}{
+		{0, image.Pt(3, 2), []uint8{1, 2, 3, 4, 5, 6}},
+		{1, image.Pt(3, 2), []uint8{1, 2, 3, 4, 5, 6}},
+		{2, image.Pt(3, 2), []uint8{3, 2, 1, 6, 5, 4}},
+		{3, image.Pt(3, 2), []uint8{6, 5, 4, 3, 2, 1}},
+		{4, image.Pt(3, 2), []uint8{4, 5, 6, 1, 2, 3}},
+		{5, image.Pt(2, 3), []uint8{1, 4, 2, 5, 3, 6}},
+		{6, image.Pt(2, 3), []uint8{4, 1, 5, 2, 6, 3}},
+		{7, image.Pt(2, 3), []uint8{6, 3, 5, 2, 4, 1}},
+		{8, image.Pt(2, 3), []uint8{3, 6, 2, 5, 1, 4}},
+		{9, image.Pt(3, 2), []uint8{1, 2, 3, 4, 5, 6}},
+	}
+
+	for _, d := range testData {
+		f := Orientation(d.orientation)
+		img1 := image.NewGray(f.Bounds(img0.Bounds()))
+		f.Draw(img1, img0, nil)
+
+		if img1.Bounds().Size() != d.size {
+			t.Errorf("orientation %d: expected %v got %v", d.orientation, d.size, img1.Bounds().Size())
+		}
+		if !bytes.Equal(d.pix, img1.Pix) {
+			t.Errorf("orientation %d: expected %v got %v", d.orientation, d.pix, img1.Pix)
+		}
+	}
+}
@@ -88,10 +88,18 @@ func sincosf32(a float32) (float32, float32) {
 	return float32(sin), float32(cos)
 }
 
+func cosf32(x float32) float32 {
+	return float32(math.Cos(float64(x)))
+}
+
 func floorf32(x float32) float32 {
 	return float32(math.Floor(float64(x)))
 }
 
+func ceilf32(x float32) float32 {
+	return float32(math.Ceil(float64(x)))
+}
+
 func sqrtf32(x float32) float32 {
 	return float32(math.Sqrt(float64(x)))
 }